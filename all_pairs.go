@@ -0,0 +1,194 @@
+package graph
+
+// AllPairsShortestPathsOf holds the shortest distances between every
+// pair of vertices in a graph. Row and column i of Dist, Next and
+// Reachable all refer to Vertices[i].
+type AllPairsShortestPathsOf[K comparable, W Number] struct {
+	Vertices  []VertexOf[K]
+	Dist      [][]W            // [From][To]Distance, only set where Reachable
+	Next      [][]EdgeOf[K, W] // [From][To]First edge on the path, zero Edge if there is none
+	Reachable [][]bool         // [From][To]Whether there is a path
+	index     map[VertexOf[K]]int
+}
+
+// AllPairsShortestPaths holds the shortest paths of a graph
+// with string vertex IDs and int64 weights.
+type AllPairsShortestPaths = AllPairsShortestPathsOf[string, int64]
+
+func newAllPairsShortestPaths[K comparable, W Number](vertices []VertexOf[K]) *AllPairsShortestPathsOf[K, W] {
+	a := &AllPairsShortestPathsOf[K, W]{
+		Vertices:  vertices,
+		Dist:      make([][]W, len(vertices)),
+		Next:      make([][]EdgeOf[K, W], len(vertices)),
+		Reachable: make([][]bool, len(vertices)),
+		index:     make(map[VertexOf[K]]int, len(vertices)),
+	}
+	for i, v := range vertices {
+		a.index[v] = i
+		a.Dist[i] = make([]W, len(vertices))
+		a.Next[i] = make([]EdgeOf[K, W], len(vertices))
+		a.Reachable[i] = make([]bool, len(vertices))
+		a.Reachable[i][i] = true
+	}
+
+	return a
+}
+
+// Distance returns the length of the shortest path from one vertex
+// to another, and false if there is no such path.
+func (a *AllPairsShortestPathsOf[K, W]) Distance(from, to VertexOf[K]) (W, bool) {
+	i, ok := a.index[from]
+	if !ok {
+		return 0, false
+	}
+	j, ok := a.index[to]
+	if !ok {
+		return 0, false
+	}
+	if !a.Reachable[i][j] {
+		return 0, false
+	}
+
+	return a.Dist[i][j], true
+}
+
+// Path returns the edges on the shortest path from one vertex to
+// another by following the next-hop table. It returns nil if there
+// is no path, and an empty path if from and to are the same vertex.
+func (a *AllPairsShortestPathsOf[K, W]) Path(from, to VertexOf[K]) []EdgeOf[K, W] {
+	if _, ok := a.Distance(from, to); !ok {
+		return nil
+	}
+
+	path := []EdgeOf[K, W]{}
+	j := a.index[to]
+	for v := from; v != to; {
+		edge := a.Next[a.index[v]][j]
+		path = append(path, edge)
+		v = edge.End
+	}
+
+	return path
+}
+
+// FloydWarshall finds the shortest paths between all pairs of vertices
+// using the Floyd-Warshall algorithm. It runs in O(V^3) regardless of
+// the number of edges, which makes it a good fit for dense graphs.
+// Negative edge weights are allowed, but if the graph contains a
+// negative cycle a *NegativeCycleError is returned.
+func (d *DirectedGraphOf[K, W]) FloydWarshall() (*AllPairsShortestPathsOf[K, W], error) {
+	a := newAllPairsShortestPaths[K, W](d.vertices)
+	dist, next, reachable := a.Dist, a.Next, a.Reachable
+
+	for _, u := range d.vertices {
+		i := a.index[u]
+		for _, edge := range d.edges[u] {
+			j := a.index[edge.End]
+			if !reachable[i][j] || edge.Weight < dist[i][j] {
+				dist[i][j] = edge.Weight
+				next[i][j] = edge
+				reachable[i][j] = true
+			}
+		}
+	}
+
+	for k := range d.vertices {
+		for i := range d.vertices {
+			if !reachable[i][k] {
+				continue
+			}
+			for j := range d.vertices {
+				if !reachable[k][j] {
+					continue
+				}
+				if alt := dist[i][k] + dist[k][j]; !reachable[i][j] || alt < dist[i][j] {
+					dist[i][j] = alt
+					next[i][j] = next[i][k]
+					reachable[i][j] = true
+				}
+			}
+		}
+
+		// Stop as soon as a vertex can reach itself with a
+		// negative distance, before the distances run away.
+		for i, v := range d.vertices {
+			if dist[i][i] < 0 {
+				return nil, d.negativeCycleThrough(v)
+			}
+		}
+	}
+
+	return a, nil
+}
+
+// negativeCycleThrough returns the error describing a
+// negative cycle which is known to be reachable from v.
+func (d *DirectedGraphOf[K, W]) negativeCycleThrough(v VertexOf[K]) error {
+	_, err := d.BellmanFord(v)
+	return err
+}
+
+// potentials computes a potential h for every vertex such that
+// w(u, v) + h[u] - h[v] >= 0 for every edge, by finding the shortest
+// paths from a virtual source connected to every vertex by a zero
+// weight edge. Edges for which weight returns false are left out.
+func (d *DirectedGraphOf[K, W]) potentials(weight func(EdgeOf[K, W]) (W, bool)) (map[VertexOf[K]]W, error) {
+	h := make(map[VertexOf[K]]W, len(d.vertices))
+	for _, v := range d.vertices {
+		h[v] = 0 // Distance from the virtual source
+	}
+
+	if _, err := d.bellmanFord(h, weight); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// Johnson finds the shortest paths between all pairs of vertices using
+// Johnson's algorithm. The edges are first reweighted with Bellman-Ford
+// so that none are negative, after which Dijkstra is run from every
+// vertex. It runs in O(VE log V), which beats FloydWarshall on sparse
+// graphs. If the graph contains a negative cycle a *NegativeCycleError
+// is returned.
+func (d *DirectedGraphOf[K, W]) Johnson() (*AllPairsShortestPathsOf[K, W], error) {
+	h, err := d.potentials(func(e EdgeOf[K, W]) (W, bool) { return e.Weight, true })
+	if err != nil {
+		return nil, err
+	}
+	reweighted := func(e EdgeOf[K, W]) W {
+		return e.Weight + h[e.Start] - h[e.End]
+	}
+
+	a := newAllPairsShortestPaths[K, W](d.vertices)
+	for i, source := range d.vertices {
+		tree := d.dijkstra(source, reweighted)
+
+		// firstEdge finds the first edge on the path to v,
+		// remembering the answers along the way.
+		first := make(map[VertexOf[K]]EdgeOf[K, W])
+		var firstEdge func(v VertexOf[K]) EdgeOf[K, W]
+		firstEdge = func(v VertexOf[K]) EdgeOf[K, W] {
+			if edge, ok := first[v]; ok {
+				return edge
+			}
+			edge := tree.Predecessors[v]
+			if edge.Start != source {
+				edge = firstEdge(edge.Start)
+			}
+			first[v] = edge
+			return edge
+		}
+
+		for v, dist := range tree.Distances {
+			j := a.index[v]
+			a.Dist[i][j] = dist - h[source] + h[v]
+			a.Reachable[i][j] = true
+			if v != source {
+				a.Next[i][j] = firstEdge(v)
+			}
+		}
+	}
+
+	return a, nil
+}
@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+)
+
+var allPairsAlgorithms = map[string]func(*DirectedGraph) (*AllPairsShortestPaths, error){
+	"FloydWarshall": (*DirectedGraph).FloydWarshall,
+	"Johnson":       (*DirectedGraph).Johnson,
+}
+
+// checkPath checks that path leads from one vertex to another
+// with the given total weight.
+func checkPath(t *testing.T, path []Edge, from, to Vertex, distance int64) {
+	t.Helper()
+	v, total := from, int64(0)
+	for _, e := range path {
+		if e.Start != v {
+			t.Errorf("path %v from %s to %s is broken at %s", path, from.ID, to.ID, e.ID)
+			return
+		}
+		v = e.End
+		total += e.Weight
+	}
+	if v != to || total != distance {
+		t.Errorf("path %v from %s to %s ends at %s with weight %d, want %d", path, from.ID, to.ID, v.ID, total, distance)
+	}
+}
+
+func TestAllPairsShortestPaths(t *testing.T) {
+	g := negativeGraph("a b 4", "a c 2", "c b -3", "b d 1", "d a 1", "e a 1")
+
+	tests := []struct {
+		from, to  string
+		distance  int64
+		reachable bool
+	}{
+		{"a", "a", 0, true},
+		{"a", "b", -1, true},
+		{"a", "d", 0, true},
+		{"d", "b", 0, true},
+		{"e", "d", 1, true},
+		{"a", "e", 0, false},
+		{"a", "missing", 0, false},
+	}
+
+	for name, algorithm := range allPairsAlgorithms {
+		a, err := algorithm(g)
+		if err != nil {
+			t.Fatalf("%s failed: %s", name, err)
+		}
+		for _, test := range tests {
+			from, to := Vertex{ID: test.from}, Vertex{ID: test.to}
+			distance, ok := a.Distance(from, to)
+			if ok != test.reachable || distance != test.distance {
+				t.Errorf("%s: Distance(%s, %s) = %d, %t, want %d, %t", name, test.from, test.to, distance, ok, test.distance, test.reachable)
+			}
+			path := a.Path(from, to)
+			if !test.reachable {
+				if path != nil {
+					t.Errorf("%s: Path(%s, %s) = %v, want nil", name, test.from, test.to, path)
+				}
+				continue
+			}
+			checkPath(t, path, from, to, test.distance)
+		}
+	}
+}
+
+func TestAllPairsNegativeCycle(t *testing.T) {
+	g := negativeGraph("a b 1", "b c -2", "c b 1", "d a 1")
+	for name, algorithm := range allPairsAlgorithms {
+		var cycleErr *NegativeCycleError
+		if _, err := algorithm(g); !errors.As(err, &cycleErr) {
+			t.Errorf("%s returned %v, want a negative cycle", name, err)
+		}
+	}
+}
+
+// TestAllPairsMatchBellmanFord compares both algorithms against
+// BellmanFord from every vertex of the csv_files inputs.
+func TestAllPairsMatchBellmanFord(t *testing.T) {
+	for _, file := range []string{"benchmark2.csv", "benchmark3.csv", "edges_3_31601.csv", "edges_6_31601.csv"} {
+		g := loadTestGraph(t, file)
+		for name, algorithm := range allPairsAlgorithms {
+			a, err := algorithm(g)
+			if err != nil {
+				t.Fatalf("%s: %s failed: %s", file, name, err)
+			}
+			for _, from := range g.Vertices() {
+				tree, err := g.BellmanFord(from)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, to := range g.Vertices() {
+					want, wantOK := tree.DistanceTo(to)
+					got, ok := a.Distance(from, to)
+					if ok != wantOK || got != want {
+						t.Fatalf("%s: %s distance from %s to %s = %d, %t, want %d, %t", file, name, from.ID, to.ID, got, ok, want, wantOK)
+					}
+					if ok {
+						checkPath(t, a.Path(from, to), from, to, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkFloydWarshall(b *testing.B) {
+	g := gridGraph(15)
+	for i := 0; i < b.N; i++ {
+		if _, err := g.FloydWarshall(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkJohnson(b *testing.B) {
+	g := gridGraph(15)
+	for i := 0; i < b.N; i++ {
+		if _, err := g.Johnson(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Attributes holds extra named values attached to a vertex or an edge,
+// such as the labels, coordinates or timestamps found in additional CSV
+// columns. Values are strings, int64s, float64s or bools, and can be
+// read back with the typed accessors below.
+type Attributes map[string]any
+
+// String returns the named attribute if it is a string.
+func (a Attributes) String(name string) (string, bool) {
+	value, ok := a[name].(string)
+	return value, ok
+}
+
+// Int returns the named attribute if it is an int64.
+func (a Attributes) Int(name string) (int64, bool) {
+	value, ok := a[name].(int64)
+	return value, ok
+}
+
+// Float returns the named attribute if it is a number,
+// converting int64 values to float64.
+func (a Attributes) Float(name string) (float64, bool) {
+	switch value := a[name].(type) {
+	case float64:
+		return value, true
+	case int64:
+		return float64(value), true
+	}
+	return 0, false
+}
+
+// Bool returns the named attribute if it is a bool.
+func (a Attributes) Bool(name string) (bool, bool) {
+	value, ok := a[name].(bool)
+	return value, ok
+}
+
+// ParseAttribute converts a textual value, e.g. a CSV field, into
+// the most specific attribute type it can be parsed as: an int64,
+// a float64, a bool ("true" or "false") or else a string.
+func ParseAttribute(s string) any {
+	s = strings.TrimSpace(s)
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	// ParseFloat also accepts "NaN" and "Inf", which are more likely names
+	if f, err := strconv.ParseFloat(s, 64); err == nil && strings.ContainsAny(s, "0123456789") {
+		return f
+	}
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return s
+}
+
+// setAttribute sets the named attribute of key in attributes,
+// creating the maps as needed.
+func setAttribute[T comparable](attributes *map[T]Attributes, key T, name string, value any) {
+	if *attributes == nil {
+		// Lazily initialize
+		*attributes = make(map[T]Attributes)
+	}
+	if (*attributes)[key] == nil {
+		(*attributes)[key] = make(Attributes)
+	}
+	(*attributes)[key][name] = value
+}
+
+// mergeAttributes returns a copy of base with the attributes
+// in overrides added.
+func mergeAttributes(base, overrides Attributes) Attributes {
+	merged := make(Attributes, len(base)+len(overrides))
+	for name, value := range base {
+		merged[name] = value
+	}
+	for name, value := range overrides {
+		merged[name] = value
+	}
+	return merged
+}
+
+// FormatAttribute formats an attribute value for writing it out. Numbers
+// and bools are formatted so that ParseAttribute turns them back into
+// a value of the same type.
+func FormatAttribute(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case float64:
+		s := strconv.FormatFloat(value, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eEnN") {
+			s += ".0" // Keep it a float
+		}
+		return s
+	}
+	return fmt.Sprint(value)
+}
+
+// attributeNames returns the names of the vertex and of the edge
+// attributes used in g, both sorted.
+func attributeNames[K comparable, W Number](g GraphOf[K, W]) (vertexNames, edgeNames []string) {
+	collect := func(names map[string]bool, attributes Attributes) {
+		for name := range attributes {
+			names[name] = true
+		}
+	}
+
+	vertexSet, edgeSet := make(map[string]bool), make(map[string]bool)
+	for _, v := range g.Vertices() {
+		collect(vertexSet, g.VertexAttributes(v))
+	}
+	for _, e := range g.Edges() {
+		collect(edgeSet, g.EdgeAttributes(e))
+	}
+
+	return sortedKeys(vertexSet), sortedKeys(edgeSet)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedAttributeNames returns the names of the attributes, sorted.
+func sortedAttributeNames(attributes Attributes) []string {
+	names := make(map[string]bool, len(attributes))
+	for name := range attributes {
+		names[name] = true
+	}
+	return sortedKeys(names)
+}
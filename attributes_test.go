@@ -0,0 +1,126 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAttribute(t *testing.T) {
+	tests := []struct {
+		s    string
+		want any
+	}{
+		{"42", int64(42)},
+		{" -7 ", int64(-7)},
+		{"0.5", 0.5},
+		{"1e3", 1000.0},
+		{"true", true},
+		{"false", false},
+		{"NaN", "NaN"},
+		{"Inf", "Inf"},
+		{"n1", "n1"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if got := ParseAttribute(test.s); got != test.want {
+			t.Errorf("ParseAttribute(%q) = %#v, want %#v", test.s, got, test.want)
+		}
+	}
+}
+
+func TestFormatAttribute(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{int64(42), "42"},
+		{2.0, "2.0"},
+		{0.5, "0.5"},
+		{1e21, "1e+21"},
+		{true, "true"},
+		{"label", "label"},
+	}
+
+	for _, test := range tests {
+		got := FormatAttribute(test.value)
+		if got != test.want {
+			t.Errorf("FormatAttribute(%#v) = %q, want %q", test.value, got, test.want)
+		}
+		if back := ParseAttribute(got); back != test.value {
+			t.Errorf("ParseAttribute(FormatAttribute(%#v)) = %#v", test.value, back)
+		}
+	}
+}
+
+func TestAttributesAccessors(t *testing.T) {
+	a := Attributes{"s": "x", "i": int64(2), "f": 0.5, "b": true}
+
+	if s, ok := a.String("s"); !ok || s != "x" {
+		t.Errorf("String(s) = %q, %t", s, ok)
+	}
+	if i, ok := a.Int("i"); !ok || i != 2 {
+		t.Errorf("Int(i) = %d, %t", i, ok)
+	}
+	if f, ok := a.Float("i"); !ok || f != 2 {
+		t.Errorf("Float(i) = %g, %t", f, ok)
+	}
+	if f, ok := a.Float("f"); !ok || f != 0.5 {
+		t.Errorf("Float(f) = %g, %t", f, ok)
+	}
+	if b, ok := a.Bool("b"); !ok || !b {
+		t.Errorf("Bool(b) = %t, %t", b, ok)
+	}
+	if _, ok := a.Int("s"); ok {
+		t.Errorf("Int(s) succeeded on a string")
+	}
+	if _, ok := a.String("missing"); ok {
+		t.Errorf("String(missing) succeeded")
+	}
+}
+
+func TestReadAttributes(t *testing.T) {
+	csv := "Vertex1\tVertex2\tid\tlabel\tlength\tVertex1.x\tVertex2.x\n" +
+		"a\tb\te1\tfirst\t1.5\t0\t1\n" +
+		"b\tc\te2\tsecond\t2\t1\t2\n"
+	g, _, err := ReadDirectedGraph(strings.NewReader(csv), LoadOptions{Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e1, _ := g.EdgeByID("e1")
+	if label, _ := g.EdgeAttributes(e1).String("label"); label != "first" {
+		t.Errorf("label of e1 = %q, want first", label)
+	}
+	if length, _ := g.EdgeAttributes(e1).Float("length"); length != 1.5 {
+		t.Errorf("length of e1 = %g, want 1.5", length)
+	}
+	if x, _ := g.VertexAttributes(Vertex{ID: "c"}).Int("x"); x != 2 {
+		t.Errorf("x of c = %d, want 2", x)
+	}
+
+	// Subgraphs keep the attributes of what they keep
+	sub := g.Subgraph([]Vertex{{ID: "b"}, {ID: "c"}})
+	e2, _ := g.EdgeByID("e2")
+	if label, _ := sub.EdgeAttributes(e2).String("label"); label != "second" {
+		t.Errorf("label of e2 in the subgraph = %q, want second", label)
+	}
+	if x, _ := sub.VertexAttributes(Vertex{ID: "b"}).Int("x"); x != 1 {
+		t.Errorf("x of b in the subgraph = %d, want 1", x)
+	}
+	if sub.HasVertex(Vertex{ID: "a"}) || sub.EdgeCount() != 1 {
+		t.Errorf("subgraph of b and c has vertices %v and %d edges", sub.Vertices(), sub.EdgeCount())
+	}
+
+	edgeSub := g.EdgeSubgraph([]Edge{e1})
+	if label, _ := edgeSub.EdgeAttributes(e1).String("label"); label != "first" || edgeSub.VertexCount() != 2 {
+		t.Errorf("edge subgraph of e1 has %d vertices and label %q", edgeSub.VertexCount(), label)
+	}
+
+	if g.SetVertexAttribute(Vertex{ID: "missing"}, "x", int64(1)) {
+		t.Errorf("SetVertexAttribute succeeded on a missing vertex")
+	}
+	if g.SetEdgeAttribute(Edge{ID: "missing"}, "x", int64(1)) {
+		t.Errorf("SetEdgeAttribute succeeded on a missing edge")
+	}
+}
@@ -0,0 +1,349 @@
+package graph
+
+import "errors"
+
+// ErrNotBipartite is returned by the bipartite matching algorithms
+// when the graph is not bipartite.
+var ErrNotBipartite = errors.New("graph: graph is not bipartite")
+
+// BipartitionOf is the result of IsBipartite. For a bipartite graph
+// Left and Right hold the two sides, every edge leading from one to
+// the other. Otherwise OddCycle holds a cycle of odd length which
+// proves it, its edges in order and directed along the cycle.
+type BipartitionOf[K comparable, W Number] struct {
+	Left, Right []VertexOf[K]
+	OddCycle    []EdgeOf[K, W]
+}
+
+// Bipartition is the BipartitionOf a graph with string
+// vertex IDs and int64 weights.
+type Bipartition = BipartitionOf[string, int64]
+
+// IsBipartite reports whether the vertices of the graph can be split
+// into two sides such that every edge connects the two, and returns
+// the sides or else an odd cycle. Every connected component is colored
+// by a breadth-first search from its first vertex, which goes on the
+// left. A self loop is an odd cycle of a single edge. It runs in
+// O(V+E) time.
+func (g *UndirectedGraphOf[K, W]) IsBipartite() (*BipartitionOf[K, W], bool) {
+	sides, oddCycle := g.bipartition()
+	if oddCycle != nil {
+		return &BipartitionOf[K, W]{OddCycle: oddCycle}, false
+	}
+
+	bipartition := &BipartitionOf[K, W]{}
+	for i, v := range g.vertices {
+		if sides[i] == 0 {
+			bipartition.Left = append(bipartition.Left, v)
+		} else {
+			bipartition.Right = append(bipartition.Right, v)
+		}
+	}
+	return bipartition, true
+}
+
+// bipartition colors the vertices with sides 0 and 1, indexed like
+// vertices, or returns an odd cycle if that can not be done.
+func (g *UndirectedGraphOf[K, W]) bipartition() ([]int, []EdgeOf[K, W]) {
+	n := len(g.vertices)
+	sides := make([]int, n)
+	depths := make([]int, n)
+	parents := make([]EdgeOf[K, W], n) // [Vertex]Edge it was reached by
+	for i := range depths {
+		depths[i] = -1
+	}
+
+	queue := NewQueueOf[K](n)
+	for i, root := range g.vertices {
+		if depths[i] != -1 {
+			continue
+		}
+		depths[i] = 0
+		queue.Push(root)
+
+		for queue.Len() > 0 {
+			v := queue.Pop()
+			u := g.index[v]
+			for _, edge := range g.edges[v] {
+				w := g.index[edge.End]
+				if depths[w] == -1 {
+					depths[w] = depths[u] + 1
+					sides[w] = 1 - sides[u]
+					parents[w] = edge
+					queue.Push(edge.End)
+				} else if sides[w] == sides[u] {
+					return nil, g.oddCycle(edge, depths, parents)
+				}
+			}
+		}
+	}
+
+	return sides, nil
+}
+
+// oddCycle returns the cycle closed by edge, whose endpoints are on the
+// same side, and the paths up the search tree from them to the vertex
+// where they meet. It starts down the tree from that vertex to the
+// start of edge, and goes back up from the end of edge.
+func (g *UndirectedGraphOf[K, W]) oddCycle(edge EdgeOf[K, W], depths []int, parents []EdgeOf[K, W]) []EdgeOf[K, W] {
+	var down, up []EdgeOf[K, W]
+	u, v := edge.Start, edge.End
+	for u != v {
+		if depths[g.index[u]] >= depths[g.index[v]] {
+			down = append(down, parents[g.index[u]])
+			u = parents[g.index[u]].Start
+		} else {
+			parent := parents[g.index[v]]
+			up = append(up, parent.Reverse())
+			v = parent.Start
+		}
+	}
+
+	cycle := make([]EdgeOf[K, W], 0, len(down)+1+len(up))
+	for i := len(down) - 1; i >= 0; i-- {
+		cycle = append(cycle, down[i])
+	}
+	cycle = append(cycle, edge)
+	return append(cycle, up...)
+}
+
+// HopcroftKarp finds a maximum-cardinality matching in a bipartite
+// graph using the Hopcroft-Karp algorithm, which augments the matching
+// along a maximal set of shortest disjoint paths at a time and runs in
+// O(E√V). The edges are returned in the order they were added to the
+// graph. It returns ErrNotBipartite if the graph is not bipartite,
+// BlossomMatching handles any graph.
+func (g *UndirectedGraphOf[K, W]) HopcroftKarp() (EdgesOf[K, W], error) {
+	sides, oddCycle := g.bipartition()
+	if oddCycle != nil {
+		return nil, ErrNotBipartite
+	}
+
+	n := len(g.vertices)
+	hk := &hopcroftKarp{adj: make([][]int, n), mate: make([]int, n), dist: make([]int, n)}
+	for i, v := range g.vertices {
+		hk.mate[i] = -1
+		if sides[i] != 0 {
+			continue
+		}
+		hk.left = append(hk.left, i)
+		for _, edge := range g.edges[v] {
+			hk.adj[i] = append(hk.adj[i], g.index[edge.End])
+		}
+	}
+
+	for hk.layer() {
+		for _, u := range hk.left {
+			if hk.mate[u] == -1 {
+				hk.augment(u)
+			}
+		}
+	}
+
+	return matchedEdges(g.edgeList, g.index, hk.mate), nil
+}
+
+// hopcroftKarp holds the state of the Hopcroft-Karp algorithm.
+// Vertices are referred to by their index in the graph's vertices.
+type hopcroftKarp struct {
+	left []int   // The vertices on the left side
+	adj  [][]int // [Left vertex]Right neighbours
+	mate []int   // [Vertex]Matched vertex, -1 if unmatched
+	dist []int   // [Left vertex]Layer in the current phase, -1 if not reached
+	last int     // The layer the shortest augmenting paths end in
+}
+
+// layer labels the left vertices with their distance along alternating
+// paths from the unmatched ones, and reports whether an unmatched right
+// vertex can be reached, i.e. whether there is an augmenting path.
+func (hk *hopcroftKarp) layer() bool {
+	queue := make([]int, 0, len(hk.left))
+	for _, u := range hk.left {
+		if hk.mate[u] == -1 {
+			hk.dist[u] = 0
+			queue = append(queue, u)
+		} else {
+			hk.dist[u] = -1
+		}
+	}
+
+	found := false
+	for head := 0; head < len(queue); head++ {
+		u := queue[head]
+		if found && hk.dist[u] > hk.last {
+			break // Only the shortest paths are used
+		}
+		for _, v := range hk.adj[u] {
+			next := hk.mate[v]
+			if next == -1 {
+				found, hk.last = true, hk.dist[u]
+			} else if hk.dist[next] == -1 {
+				hk.dist[next] = hk.dist[u] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return found
+}
+
+// augment looks for a shortest augmenting path from u following the layers,
+// and flips it if found. Vertices which lead nowhere are taken out of
+// the layers so that they are not searched again in this phase.
+func (hk *hopcroftKarp) augment(u int) bool {
+	for _, v := range hk.adj[u] {
+		next := hk.mate[v]
+		if next == -1 && hk.dist[u] == hk.last || next != -1 && hk.dist[next] == hk.dist[u]+1 && hk.augment(next) {
+			hk.mate[u], hk.mate[v] = v, u
+			return true
+		}
+	}
+	hk.dist[u] = -1
+	return false
+}
+
+// Hungarian finds a maximum-weight matching in a bipartite graph, e.g.
+// the best assignment of workers to jobs given the score of each pair,
+// using the Hungarian algorithm in O(V^3) time. Like MaxWeightMatching
+// it never matches edges with a negative weight, and between parallel
+// edges it picks the heaviest. The edges are returned in the order they
+// were added to the graph. It returns ErrNotBipartite if the graph is
+// not bipartite.
+func (g *UndirectedGraphOf[K, W]) Hungarian() (EdgesOf[K, W], error) {
+	sides, oddCycle := g.bipartition()
+	if oddCycle != nil {
+		return nil, ErrNotBipartite
+	}
+
+	// Rows are the vertices on the smaller side, columns the others
+	var rows, columns []int
+	position := make([]int, len(g.vertices)) // [Vertex]Row or column
+	for i := range g.vertices {
+		if sides[i] == 0 {
+			position[i] = len(rows)
+			rows = append(rows, i)
+		} else {
+			position[i] = len(columns)
+			columns = append(columns, i)
+		}
+	}
+	if len(rows) > len(columns) {
+		rows, columns = columns, rows
+	}
+	isRow := make([]bool, len(g.vertices))
+	for _, i := range rows {
+		isRow[i] = true
+	}
+
+	// Minimize the cost -weight, with 0 for leaving a row unmatched
+	best := make([][]int, len(rows)) // [Row][Column]Heaviest edge in edgeList, -1 if none
+	cost := make([][]W, len(rows))
+	for r := range rows {
+		best[r] = make([]int, len(columns))
+		cost[r] = make([]W, len(columns))
+		for c := range columns {
+			best[r][c] = -1
+		}
+	}
+	for k, e := range g.edgeList {
+		u, v := g.index[e.Start], g.index[e.End]
+		if !isRow[u] {
+			u, v = v, u
+		}
+		r, c := position[u], position[v]
+		if e.Weight > 0 && (best[r][c] == -1 || e.Weight > g.edgeList[best[r][c]].Weight) {
+			best[r][c] = k
+			cost[r][c] = -e.Weight
+		}
+	}
+
+	assignment := hungarian(cost, len(columns))
+	matched := make([]bool, len(g.edgeList))
+	for r, c := range assignment {
+		if k := best[r][c]; k != -1 {
+			matched[k] = true
+		}
+	}
+
+	var matching EdgesOf[K, W]
+	for k, e := range g.edgeList {
+		if matched[k] {
+			matching = append(matching, e)
+		}
+	}
+	return matching, nil
+}
+
+// hungarian solves the assignment problem for the given cost matrix,
+// with no more rows than columns: it assigns every row a different
+// column, minimizing the total cost. It returns the column of every
+// row. Rows and columns are numbered from 1 inside, with row and
+// column 0 standing for none, keeping the potentials u and v such
+// that cost[i][j] - u[i] - v[j] is never negative.
+func hungarian[W Number](cost [][]W, columns int) []int {
+	n, m := len(cost), columns
+	u := make([]W, n+1)
+	v := make([]W, m+1)
+	p := make([]int, m+1)   // [Column]Assigned row
+	way := make([]int, m+1) // [Column]Previous column on the shortest path
+	minv := make([]W, m+1)
+	used := make([]bool, m+1)
+
+	for i := 1; i <= n; i++ {
+		// Find a shortest augmenting path from row i
+		p[0] = i
+		j0 := 0
+		for j := range used {
+			used[j] = false
+		}
+		first := true
+		for {
+			used[j0] = true
+			i0 := p[j0]
+			var delta W
+			j1 := -1
+			for j := 1; j <= m; j++ {
+				if used[j] {
+					continue
+				}
+				if reduced := cost[i0-1][j-1] - u[i0] - v[j]; first || reduced < minv[j] {
+					minv[j] = reduced
+					way[j] = j0
+				}
+				if j1 == -1 || minv[j] < delta {
+					delta = minv[j]
+					j1 = j
+				}
+			}
+			first = false
+
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+			if p[j0] == 0 {
+				break
+			}
+		}
+
+		// Assign along the path
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+
+	assignment := make([]int, n)
+	for j := 1; j <= m; j++ {
+		if p[j] != 0 {
+			assignment[p[j]-1] = j - 1
+		}
+	}
+	return assignment
+}
@@ -0,0 +1,226 @@
+package graph
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+// randomBipartiteGraph returns a graph of the vertices l0 to l(left-1)
+// and r0 to r(right-1), with m random edges between the two sides
+// weighted from -5 to 20.
+func randomBipartiteGraph(r *rand.Rand, left, right, m int) *UndirectedGraph {
+	g := &UndirectedGraph{}
+	for i := 0; i < left; i++ {
+		g.AddVertex(Vertex{ID: "l" + strconv.Itoa(i)})
+	}
+	for i := 0; i < right; i++ {
+		g.AddVertex(Vertex{ID: "r" + strconv.Itoa(i)})
+	}
+	for i := 0; i < m; i++ {
+		g.AddEdge(Edge{
+			Start:  Vertex{ID: "l" + strconv.Itoa(r.Intn(left))},
+			End:    Vertex{ID: "r" + strconv.Itoa(r.Intn(right))},
+			Weight: int64(r.Intn(26) - 5),
+			ID:     "e" + strconv.Itoa(i),
+		})
+	}
+	return g
+}
+
+// checkBipartition checks that b splits the vertices of g in two
+// sides every edge connects, or else holds an odd cycle of g.
+func checkBipartition(t *testing.T, name string, g *UndirectedGraph, b *Bipartition, bipartite bool) {
+	t.Helper()
+	if !bipartite {
+		edges := make(map[Edge]bool)
+		for _, e := range g.Edges() {
+			edges[e], edges[e.Reverse()] = true, true
+		}
+		cycle := b.OddCycle
+		if len(cycle)%2 != 1 {
+			t.Fatalf("%s: odd cycle %v has even length", name, cycle)
+		}
+		for i, e := range cycle {
+			if !edges[e] {
+				t.Errorf("%s: odd cycle edge %v is not in the graph", name, e)
+			}
+			if next := cycle[(i+1)%len(cycle)]; e.End != next.Start {
+				t.Errorf("%s: odd cycle %v is broken after %v", name, cycle, e)
+			}
+		}
+		return
+	}
+
+	left := make(map[Vertex]bool)
+	for _, v := range b.Left {
+		left[v] = true
+	}
+	if len(b.Left)+len(b.Right) != g.VertexCount() {
+		t.Errorf("%s: sides %v and %v do not hold the %d vertices", name, b.Left, b.Right, g.VertexCount())
+	}
+	for _, e := range g.Edges() {
+		if left[e.Start] == left[e.End] {
+			t.Errorf("%s: edge %v does not connect the sides", name, e)
+		}
+	}
+}
+
+func TestIsBipartite(t *testing.T) {
+	tests := []struct {
+		name      string
+		g         *UndirectedGraph
+		bipartite bool
+	}{
+		{"empty", &UndirectedGraph{}, true},
+		{"path", undirectedGraph("a b", "b c", "c d"), true},
+		{"even cycle", undirectedGraph("a b", "b c", "c d", "d a"), true},
+		{"triangle", undirectedGraph("a b", "b c", "c a"), false},
+		{"self loop", undirectedGraph("a b", "b b"), false},
+		{"odd cycle in a second component", undirectedGraph("a b", "c d", "d e", "e f", "f g", "g c"), false},
+		{"odd cycle behind a tail", undirectedGraph("a b", "b c", "c d", "d e", "e c", "a g"), false},
+		{"petersen", petersenGraph(), false},
+	}
+
+	for _, test := range tests {
+		b, bipartite := test.g.IsBipartite()
+		if bipartite != test.bipartite {
+			t.Errorf("%s: IsBipartite = %t, want %t", test.name, bipartite, test.bipartite)
+			continue
+		}
+		checkBipartition(t, test.name, test.g, b, bipartite)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		name := "random graph " + strconv.Itoa(i)
+		g := randomGraph(r, 2+r.Intn(9), r.Intn(12))
+		b, bipartite := g.IsBipartite()
+		checkBipartition(t, name, g, b, bipartite)
+
+		g = randomBipartiteGraph(r, 1+r.Intn(5), 1+r.Intn(5), r.Intn(12))
+		b, bipartite = g.IsBipartite()
+		if !bipartite {
+			t.Errorf("random bipartite graph %d: IsBipartite = false, with odd cycle %v", i, b.OddCycle)
+			continue
+		}
+		checkBipartition(t, name, g, b, bipartite)
+	}
+}
+
+func TestHopcroftKarp(t *testing.T) {
+	tests := []struct {
+		name string
+		g    *UndirectedGraph
+		size int
+	}{
+		{"empty", &UndirectedGraph{}, 0},
+		{"path", undirectedGraph("a b", "b c", "c d"), 2},
+		{"star", undirectedGraph("a b", "a c", "a d"), 1},
+		{"even cycle", undirectedGraph("a b", "b c", "c d", "d a"), 2},
+		{"chain", undirectedGraph("a b", "a d", "c b", "c f", "e d"), 3},
+		{"parallel edges", undirectedGraph("a b 1", "a b 2"), 1},
+	}
+
+	for _, test := range tests {
+		matching, err := test.g.HopcroftKarp()
+		if err != nil {
+			t.Errorf("%s: HopcroftKarp failed: %s", test.name, err)
+			continue
+		}
+		if len(matching) != test.size {
+			t.Errorf("%s: matched %d edges %v, want %d", test.name, len(matching), matching, test.size)
+		}
+		checkMatching(t, test.name, test.g, matching)
+	}
+
+	for _, g := range []*UndirectedGraph{undirectedGraph("a b", "b c", "c a"), undirectedGraph("a a"), petersenGraph()} {
+		if _, err := g.HopcroftKarp(); !errors.Is(err, ErrNotBipartite) {
+			t.Errorf("HopcroftKarp on %v returned %v, want %v", g.Edges(), err, ErrNotBipartite)
+		}
+	}
+}
+
+func TestHopcroftKarpMatchesBlossom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		name := "random bipartite graph " + strconv.Itoa(i)
+		g := randomBipartiteGraph(r, 1+r.Intn(12), 1+r.Intn(12), r.Intn(40))
+
+		matching, err := g.HopcroftKarp()
+		if err != nil {
+			t.Fatalf("%s: HopcroftKarp failed: %s", name, err)
+		}
+		checkMatching(t, name, g, matching)
+		if want := len(g.BlossomMatching()); len(matching) != want {
+			t.Errorf("%s: matched %d edges, BlossomMatching %d\n%v", name, len(matching), want, g.Edges())
+		}
+	}
+}
+
+func BenchmarkHopcroftKarp(b *testing.B) {
+	g := randomBipartiteGraph(rand.New(rand.NewSource(1)), 500, 500, 3000)
+	for i := 0; i < b.N; i++ {
+		g.HopcroftKarp()
+	}
+}
+
+func TestHungarian(t *testing.T) {
+	tests := []struct {
+		name   string
+		g      *UndirectedGraph
+		weight int64
+	}{
+		{"empty", &UndirectedGraph{}, 0},
+		{"negative edge", undirectedGraph("a b -1"), 0},
+		// Giving w1 its best job leaves w2 and w3 with poor ones
+		{"assignment", undirectedGraph("w1 j1 9", "w1 j2 8", "w2 j1 8", "w2 j3 1", "w3 j1 7", "w3 j2 1"), 16},
+		{"more jobs than workers", undirectedGraph("w1 j1 2", "w1 j2 3", "w1 j3 1"), 3},
+		{"parallel edges", undirectedGraph("a b 1", "b a 4"), 4},
+	}
+
+	for _, test := range tests {
+		matching, err := test.g.Hungarian()
+		if err != nil {
+			t.Errorf("%s: Hungarian failed: %s", test.name, err)
+			continue
+		}
+		checkMatching(t, test.name, test.g, matching)
+		if weight := matchingWeight(matching); weight != test.weight {
+			t.Errorf("%s: matched %v of weight %d, want %d", test.name, matching, weight, test.weight)
+		}
+	}
+
+	if _, err := undirectedGraph("a b 1", "b c 1", "c a 1").Hungarian(); !errors.Is(err, ErrNotBipartite) {
+		t.Errorf("Hungarian on a triangle returned %v, want %v", err, ErrNotBipartite)
+	}
+}
+
+func TestHungarianBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		name := "random bipartite graph " + strconv.Itoa(i)
+		g := randomBipartiteGraph(r, 1+r.Intn(5), 1+r.Intn(5), r.Intn(16))
+
+		matching, err := g.Hungarian()
+		if err != nil {
+			t.Fatalf("%s: Hungarian failed: %s", name, err)
+		}
+		checkMatching(t, name, g, matching)
+		want := bruteForceMaxWeight(g)
+		if weight := matchingWeight(matching); weight != want {
+			t.Errorf("%s: matched weight %d, want %d\n%v", name, weight, want, g.Edges())
+		}
+		if weight := matchingWeight(g.MaxWeightMatching()); weight != want {
+			t.Errorf("%s: MaxWeightMatching weight %d, want %d", name, weight, want)
+		}
+	}
+}
+
+func BenchmarkHungarian(b *testing.B) {
+	g := randomBipartiteGraph(rand.New(rand.NewSource(1)), 100, 100, 2000)
+	for i := 0; i < b.N; i++ {
+		g.Hungarian()
+	}
+}
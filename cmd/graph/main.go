@@ -0,0 +1,478 @@
+// Command graph runs the algorithms in the graph package
+// on graphs read from CSV files. An input file named "-" is read
+// from stdin, and gzip compressed input is decompressed.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/njern/graph"
+)
+
+var (
+	shortest_path     = flag.String("shortest_path", "", "The CSV file from which to read the input graph.")
+	all_pairs         = flag.String("all_pairs", "dijkstra", "Algorithm used for -shortest_path: dijkstra, floyd_warshall or johnson.")
+	prim              = flag.String("prim", "", "The CSV file from which to read the input graph for calculating Minimum Spanning Trees (exercise 3).")
+	vertex_colors     = flag.String("vertex_colors", "", "The CSV file from which to read the input graph for calculating minimum vertex coloring (exercise 4).")
+	edge_colors       = flag.String("edge_colors", "", "The CSV file from which to read the input graph for calculating minimum edge coloring (exercise 4).")
+	max_card_matching = flag.String("max_card_matching", "", "The CSV file from which to read the input graph for calculating a maximum-cardinality edge matching in an undirected graph (exercise 5).")
+	seed              = flag.Int64("seed", 0, "Seed for the randomized algorithms, such as -matching_algorithm greedy, to replay a run (default from the current time, which is logged)")
+	max_weight        = flag.String("max_weight_matching", "", "The CSV file from which to read the input graph for calculating a maximum-weight edge matching in an undirected graph.")
+	perfect           = flag.Bool("perfect", false, "Find a minimum-weight perfect matching with -max_weight_matching instead")
+	matching          = flag.String("matching_algorithm", "auto", "Algorithm used for -max_card_matching: auto (hopcroft_karp for bipartite graphs, else blossom), blossom, hopcroft_karp or greedy. For -max_weight_matching: auto (hungarian for bipartite graphs, else blossom), blossom or hungarian.")
+	max_flow          = flag.String("max_flow", "", "Find max flow from a directed graph (exercise 6).")
+	min_cut           = flag.String("min_cut", "", "Find a minimum s-t cut in a directed graph, using -source and -sink.")
+	min_cost_flow     = flag.String("min_cost_flow", "", "Find a minimum cost flow in a directed graph with capacity and cost columns, using -source, -sink and -demand.")
+	demand            = flag.Int64("demand", -1, "Units of flow to send for min cost flow, -1 for the maximum flow")
+	max_flow_source   = flag.String("source", "", "Source for max flow, min cut and min cost flow, and of the shortest paths drawn by -dot (vertex ID)")
+	max_flow_sink     = flag.String("sink", "", "Sink for max flow, min cut and min cost flow, and target of the shortest path drawn by -dot (vertex ID)")
+	dot               = flag.String("dot", "", "Also write the input graph with the result highlighted in DOT to the given file, - for stdout instead of the text output")
+	convert           = flag.String("convert", "", "Convert the given input graph to the output file named by the first argument, e.g. -convert in.csv out.dot")
+	format            = flag.String("format", "", "Output format for -convert: csv, dot, graphml or json (default from the output file extension)")
+	undirected        = flag.Bool("undirected", false, "Read the input graph of -convert as undirected")
+	fields            = flag.String("fields", "", "Comma separated fields of the columns of input files without a header row, e.g. source,target,id (default source,target,weight,id)")
+	input_format      = flag.String("input_format", "", "Format of the input graphs: csv, graphml, gml, dot, matrixmarket or edgelist (default from the input file extension, else csv)")
+	strict            = flag.Bool("strict", false, "Fail on rows of the input file which are not valid edges, instead of skipping them")
+	multigraph        = flag.Bool("multigraph", false, "Read the input graph as a multigraph, dropping edges with the ID of an earlier edge")
+)
+
+func parseFlags() {
+	flag.Parse()
+}
+
+func init() {
+	parseFlags() // Parse flags
+}
+
+// loadOptions returns the options used to read the input graph at filePath.
+func loadOptions(filePath string) graph.LoadOptions {
+	opts := graph.LoadOptions{Separator: '\t', Strict: *strict, Multigraph: *multigraph, Format: graph.Format(*input_format)}
+	if *fields != "" {
+		opts.Fields = strings.Split(*fields, ",")
+	}
+	if opts.Format == "" {
+		opts.Format, _ = graph.FormatFromPath(filePath)
+	}
+
+	return opts
+}
+
+// openInput opens the input file at filePath, stdin if it is "-".
+// Gzip compressed input is decompressed by the loaders.
+func openInput(filePath string) (io.ReadCloser, error) {
+	if filePath == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(filePath)
+}
+
+// readGraph reads a graph from the input file at filePath using
+// read, exiting if it can not be read and logging the skipped rows.
+func readGraph[G any](filePath string, read func(io.Reader, graph.LoadOptions) (G, []*graph.ParseError, error)) G {
+	in, err := openInput(filePath)
+	if err != nil {
+		log.Fatalf("Parsing graph failed with error: %s\n", err)
+	}
+	defer in.Close()
+
+	g, warnings, err := read(in, loadOptions(filePath))
+	var parseErr *graph.ParseError
+	if errors.As(err, &parseErr) {
+		parseErr.File = filePath
+	}
+	if err != nil {
+		log.Fatalf("Parsing graph failed with error: %s\n", err)
+	}
+	for _, warning := range warnings {
+		warning.File = filePath
+		log.Printf("Skipped row: %s\n", warning)
+	}
+
+	return g
+}
+
+// loadDirectedGraph reads the directed graph at filePath.
+func loadDirectedGraph(filePath string) *graph.DirectedGraph {
+	return readGraph(filePath, graph.ReadDirectedGraph)
+}
+
+// loadUndirectedGraph reads the undirected graph at filePath.
+func loadUndirectedGraph(filePath string) *graph.UndirectedGraph {
+	return readGraph(filePath, graph.ReadUndirectedGraph)
+}
+
+// randomOptions returns the options for the randomized algorithms,
+// logging the seed if it was not given so the run can be replayed.
+func randomOptions() graph.RandomOptions {
+	seeded := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			seeded = true
+		}
+	})
+	if !seeded {
+		*seed = time.Now().UnixNano()
+		log.Printf("Using -seed %d\n", *seed)
+	}
+
+	return graph.RandomOptions{Seed: *seed}
+}
+
+// results is where the text output of the algorithms is written.
+var results io.Writer = os.Stdout
+
+// writeHighlighted writes g with h drawn on top to the -dot file.
+func writeHighlighted(g graph.Graph, h graph.Highlight) {
+	write := func(w io.Writer, g graph.Graph) error {
+		return graph.WriteHighlightedDOT(w, g, h)
+	}
+	if err := writeFile(g, *dot, write); err != nil {
+		log.Fatalf("Writing graph failed with error: %s\n", err)
+	}
+}
+
+// writers maps the output formats to their writers.
+var writers = map[graph.Format]func(io.Writer, graph.Graph) error{
+	graph.FormatCSV:     graph.WriteCSV,
+	graph.FormatDOT:     graph.WriteDOT,
+	graph.FormatGraphML: graph.WriteGraphML,
+	graph.FormatJSON:    graph.WriteJSON,
+}
+
+// writeGraph writes g to the file at filePath, stdout if it is "-",
+// in the given format or else the one matching the file extension.
+// Output is never compressed, so .gz files need a format.
+func writeGraph(g graph.Graph, filePath string, format graph.Format) error {
+	if format == "" && !strings.EqualFold(filepath.Ext(filePath), ".gz") {
+		format, _ = graph.FormatFromPath(filePath)
+	}
+	write, ok := writers[format]
+	if !ok {
+		return fmt.Errorf("unknown output format for %q, use -format", filePath)
+	}
+
+	return writeFile(g, filePath, write)
+}
+
+// writeFile writes g to the file at filePath, stdout if it is "-", using write.
+func writeFile(g graph.Graph, filePath string, write func(io.Writer, graph.Graph) error) error {
+	if filePath == "-" {
+		return write(os.Stdout, g)
+	}
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	if err := write(file, g); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// weightless reports whether g has edges, all of which lack a weight.
+// The loaders give edges of files without a weight column weight -1.
+func weightless(g graph.Graph) bool {
+	edges := g.Edges()
+	for _, e := range edges {
+		if e.Weight != -1 {
+			return false
+		}
+	}
+	return len(edges) > 0
+}
+
+// printShortestPaths prints the distance from the tree's
+// source to each of the given vertices, one per line.
+func printShortestPaths(tree *graph.ShortestPathTree, vertices []graph.Vertex) {
+	for _, v := range vertices {
+		if dist, ok := tree.DistanceTo(v); ok {
+			fmt.Fprintf(results, "%s\t%s\t%d\n", tree.Source.ID, v.ID, dist)
+		} else {
+			fmt.Fprintf(results, "%s\t%s\tNo path!\n", tree.Source.ID, v.ID)
+		}
+	}
+}
+
+// printAllPairsShortestPaths prints the distance between every
+// pair of vertices in the same format as printShortestPaths.
+func printAllPairsShortestPaths(apsp *graph.AllPairsShortestPaths) {
+	for _, source := range apsp.Vertices {
+		for _, v := range apsp.Vertices {
+			if dist, ok := apsp.Distance(source, v); ok {
+				fmt.Fprintf(results, "%s\t%s\t%d\n", source.ID, v.ID, dist)
+			} else {
+				fmt.Fprintf(results, "%s\t%s\tNo path!\n", source.ID, v.ID)
+			}
+		}
+	}
+}
+
+func main() {
+	if *dot == "-" {
+		results = io.Discard
+	}
+
+	if *shortest_path != "" {
+		d := loadDirectedGraph(*shortest_path)
+		if weightless(d) {
+			log.Fatalf("Finding shortest paths failed: %s has no edge weights, give it a weight column\n", *shortest_path)
+		}
+
+		switch *all_pairs {
+		case "dijkstra":
+			for _, source := range d.Vertices() {
+				tree, err := d.ShortestPaths(source)
+				if err != nil {
+					log.Fatalf("Finding shortest paths failed with error: %s\n", err)
+				}
+				printShortestPaths(tree, d.Vertices())
+			}
+		case "floyd_warshall", "johnson":
+			var apsp *graph.AllPairsShortestPaths
+			var err error
+			if *all_pairs == "floyd_warshall" {
+				apsp, err = d.FloydWarshall()
+			} else {
+				apsp, err = d.Johnson()
+			}
+			if err != nil {
+				log.Fatalf("Finding shortest paths failed with error: %s\n", err)
+			}
+			printAllPairsShortestPaths(apsp)
+		default:
+			log.Fatalf("Unknown all pairs algorithm %q\n", *all_pairs)
+		}
+
+		if *dot != "" {
+			// Draw the shortest path tree from the source, and the path to the sink
+			if *max_flow_source == "" {
+				log.Fatalf("Drawing shortest paths needs a -source\n")
+			}
+			tree, err := d.ShortestPaths(graph.Vertex{ID: *max_flow_source})
+			if err != nil {
+				log.Fatalf("Finding shortest paths failed with error: %s\n", err)
+			}
+			var h graph.Highlight
+			for _, edge := range tree.Predecessors {
+				h.Edges = append(h.Edges, edge)
+			}
+			if *max_flow_sink != "" {
+				h.Path = tree.PathTo(graph.Vertex{ID: *max_flow_sink})
+			}
+			writeHighlighted(d, h)
+		}
+	} else if *prim != "" {
+		d := loadUndirectedGraph(*prim)
+
+		if d.VertexCount() == 0 {
+			log.Fatalf("Finding a minimum spanning tree failed: %s has no edges\n", *prim)
+		}
+		edges := d.PrimMST(d.Vertices()[0])
+		var edgeLabels []string
+		//var totalWeight int64
+		for _, edge := range edges {
+			edgeLabels = append(edgeLabels, edge.ID)
+			//totalWeight += edge.Weight
+		}
+		sort.Strings(edgeLabels)
+
+		fmt.Fprintf(results, "%s\n", strings.Join(edgeLabels, ","))
+		// fmt.Printf("total weight: %d\n", totalWeight)
+
+		if *dot != "" {
+			writeHighlighted(d, graph.Highlight{Edges: edges})
+		}
+	} else if *vertex_colors != "" {
+		d := loadUndirectedGraph(*vertex_colors)
+
+		vertexColors := d.VertexColors()
+		for _, vertex := range d.Vertices() {
+			fmt.Fprintf(results, "%s: %d\n", vertex.ID, vertexColors[vertex])
+		}
+
+		if *dot != "" {
+			writeHighlighted(d, graph.Highlight{VertexColors: vertexColors})
+		}
+	} else if *edge_colors != "" {
+		d := loadUndirectedGraph(*edge_colors)
+
+		vertexColors := d.EdgeColors()
+		for _, edge := range d.Edges() {
+			fmt.Fprintf(results, "%s: %d\n", edge.ID, vertexColors[edge])
+		}
+
+		if *dot != "" {
+			writeHighlighted(d, graph.Highlight{EdgeColors: vertexColors})
+		}
+	} else if *max_card_matching != "" {
+		d := loadUndirectedGraph(*max_card_matching)
+
+		var edges graph.Edges
+		algorithm := *matching
+		if algorithm == "auto" {
+			algorithm = "blossom"
+			if _, bipartite := d.IsBipartite(); bipartite {
+				algorithm = "hopcroft_karp"
+			}
+		}
+		switch algorithm {
+		case "blossom":
+			edges = d.BlossomMatching()
+		case "hopcroft_karp":
+			var err error
+			if edges, err = d.HopcroftKarp(); err != nil {
+				log.Fatalf("Finding matching failed with error: %s\n", err)
+			}
+		case "greedy":
+			edges = d.GreedyMatching(graph.MatchingOptions{Iterations: 10000, RandomOptions: randomOptions()})
+		default:
+			log.Fatalf("Unknown matching algorithm %q\n", *matching)
+		}
+		var edgeLabels []string
+		for _, edge := range edges {
+			edgeLabels = append(edgeLabels, edge.ID)
+		}
+		sort.Strings(edgeLabels)
+		fmt.Fprintf(results, "%s\n", strings.Join(edgeLabels, ","))
+
+		if *dot != "" {
+			writeHighlighted(d, graph.Highlight{Edges: edges})
+		}
+	} else if *max_weight != "" {
+		d := loadUndirectedGraph(*max_weight)
+
+		var edges graph.Edges
+		var err error
+		algorithm := *matching
+		if algorithm == "auto" {
+			algorithm = "blossom"
+			if _, bipartite := d.IsBipartite(); bipartite && !*perfect {
+				algorithm = "hungarian"
+			}
+		}
+		switch {
+		case *perfect && algorithm == "blossom":
+			edges, err = d.MinWeightPerfectMatching()
+		case algorithm == "blossom":
+			edges = d.MaxWeightMatching()
+		case algorithm == "hungarian" && *perfect:
+			log.Fatalf("Minimum-weight perfect matchings are only found by blossom\n")
+		case algorithm == "hungarian":
+			edges, err = d.Hungarian()
+		default:
+			log.Fatalf("Unknown matching algorithm %q\n", *matching)
+		}
+		if err != nil {
+			log.Fatalf("Finding matching failed with error: %s\n", err)
+		}
+
+		var edgeLabels []string
+		var totalWeight int64
+		for _, edge := range edges {
+			edgeLabels = append(edgeLabels, edge.ID)
+			totalWeight += edge.Weight
+		}
+		sort.Strings(edgeLabels)
+		fmt.Fprintf(results, "%s\n", strings.Join(edgeLabels, ","))
+		fmt.Fprintf(results, "\n\nWeight: %d\n", totalWeight)
+
+		if *dot != "" {
+			writeHighlighted(d, graph.Highlight{Edges: edges})
+		}
+	} else if *max_flow != "" {
+		d := loadDirectedGraph(*max_flow)
+
+		var source, sink graph.Vertex
+
+		for _, v := range d.Vertices() {
+			if v.ID == *max_flow_source {
+				source = v
+			} else if v.ID == *max_flow_sink {
+				sink = v
+			}
+		}
+
+		usedCapacity, maxFlow := d.FindMaxFlow(source, sink)
+		for _, edge := range d.Edges() {
+			if usedCap, ok := usedCapacity[edge]; ok {
+				fmt.Fprintf(results, "%s: %d\n", edge.ID, usedCap)
+			}
+		}
+		fmt.Fprintf(results, "\n\nMax flow: %d\n", maxFlow)
+
+		if *dot != "" {
+			writeHighlighted(d, graph.Highlight{Flow: usedCapacity})
+		}
+	} else if *min_cut != "" {
+		d := loadDirectedGraph(*min_cut)
+
+		cut := d.MinCut(graph.Vertex{ID: *max_flow_source}, graph.Vertex{ID: *max_flow_sink})
+		var sourceLabels, edgeLabels []string
+		for _, v := range cut.SourceSide {
+			sourceLabels = append(sourceLabels, v.ID)
+		}
+		for edge, capacity := range cut.Edges {
+			edgeLabels = append(edgeLabels, fmt.Sprintf("%s: %d", edge.ID, capacity))
+		}
+		sort.Strings(sourceLabels)
+		sort.Strings(edgeLabels)
+
+		fmt.Fprintf(results, "Source side: %s\n", strings.Join(sourceLabels, ","))
+		fmt.Fprintf(results, "%s\n", strings.Join(edgeLabels, "\n"))
+		fmt.Fprintf(results, "\n\nMin cut: %d\n", cut.Capacity)
+
+		if *dot != "" {
+			var h graph.Highlight
+			for edge := range cut.Edges {
+				h.Edges = append(h.Edges, edge)
+			}
+			writeHighlighted(d, h)
+		}
+	} else if *min_cost_flow != "" {
+		d := loadDirectedGraph(*min_cost_flow)
+
+		result, err := d.MinCostFlow(graph.Vertex{ID: *max_flow_source}, graph.Vertex{ID: *max_flow_sink}, *demand)
+		if err == graph.ErrInsufficientCapacity {
+			log.Printf("Could only send %d of %d units of flow\n", result.Value, *demand)
+		} else if err != nil {
+			log.Fatalf("Finding min cost flow failed with error: %s\n", err)
+		}
+
+		var edgeLabels []string
+		for edge, flow := range result.Flow {
+			edgeLabels = append(edgeLabels, fmt.Sprintf("%s: %d", edge.ID, flow))
+		}
+		sort.Strings(edgeLabels)
+
+		fmt.Fprintf(results, "%s\n", strings.Join(edgeLabels, "\n"))
+		fmt.Fprintf(results, "\n\nFlow: %d\nCost: %d\n", result.Value, result.Cost)
+
+		if *dot != "" {
+			writeHighlighted(d, graph.Highlight{Flow: result.Flow})
+		}
+	} else if *convert != "" {
+		if flag.NArg() != 1 {
+			log.Fatalf("Usage: -convert input output\n")
+		}
+
+		var g graph.Graph
+		if *undirected {
+			g = loadUndirectedGraph(*convert)
+		} else {
+			g = loadDirectedGraph(*convert)
+		}
+		if err := writeGraph(g, flag.Arg(0), graph.Format(*format)); err != nil {
+			log.Fatalf("Writing graph failed with error: %s\n", err)
+		}
+	}
+}
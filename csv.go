@@ -0,0 +1,474 @@
+package graph
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Names of the Edge fields the columns of a CSV file can be read
+// into, together with WeightAttribute, CapacityAttribute and
+// CostAttribute. See LoadOptions.
+const (
+	SourceField = "source"
+	TargetField = "target"
+	IDField     = "id"
+)
+
+// DefaultFields are the fields of the columns of a CSV file
+// without a header row, unless LoadOptions.Fields says otherwise.
+var DefaultFields = []string{SourceField, TargetField, WeightAttribute, IDField}
+
+// WeightlessFields are the fields of the rows of a CSV file without
+// a header row which have one value less than DefaultFields, as some
+// graphs leave out the weight. They are only used along with
+// DefaultFields, when LoadOptions.Fields is nil.
+var WeightlessFields = []string{SourceField, TargetField, IDField}
+
+// fields are the Edge fields columns can be read into.
+var fields = map[string]bool{
+	SourceField:       true,
+	TargetField:       true,
+	IDField:           true,
+	WeightAttribute:   true,
+	CapacityAttribute: true,
+	CostAttribute:     true,
+}
+
+// headerColumns maps the (lower case) header names understood by
+// default to the field the column is read into.
+var headerColumns = map[string]string{
+	"vertex1":         SourceField,
+	"start":           SourceField,
+	"source":          SourceField,
+	"from":            SourceField,
+	"vertex2":         TargetField,
+	"end":             TargetField,
+	"target":          TargetField,
+	"to":              TargetField,
+	"id":              IDField,
+	WeightAttribute:   WeightAttribute,
+	CapacityAttribute: CapacityAttribute,
+	CostAttribute:     CostAttribute,
+}
+
+// headerNames merges the default header names with the custom ones.
+func (opts *LoadOptions) headerNames() (map[string]string, error) {
+	names := make(map[string]string, len(headerColumns)+len(opts.Columns))
+	for name, field := range headerColumns {
+		names[name] = field
+	}
+	for name, field := range opts.Columns {
+		if !fields[field] {
+			return nil, fmt.Errorf("graph: unknown field %q for column %q", field, name)
+		}
+		names[strings.ToLower(strings.TrimSpace(name))] = field
+	}
+
+	return names, nil
+}
+
+// positionalColumns returns the columns of a file without a header row.
+func (opts *LoadOptions) positionalColumns() (*csvColumns, error) {
+	if opts.Fields != nil {
+		return fieldColumns(opts.Fields)
+	}
+
+	columns, err := fieldColumns(DefaultFields)
+	if err != nil {
+		return nil, err
+	}
+	if columns.weightless, err = fieldColumns(WeightlessFields); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// fieldColumns returns the columns of rows holding the given fields.
+func fieldColumns(fieldList []string) (*csvColumns, error) {
+	columns := newCSVColumns()
+	columns.width = len(fieldList)
+	for i, field := range fieldList {
+		if !fields[field] {
+			return nil, fmt.Errorf("graph: unknown field %q", field)
+		}
+		if _, seen := columns.fields[field]; seen {
+			return nil, fmt.Errorf("graph: field %q given twice", field)
+		}
+		columns.fields[field] = i
+	}
+	if !columns.hasEndpoints() {
+		return nil, errors.New("graph: fields must include both source and target")
+	}
+
+	return columns, nil
+}
+
+// csvColumns holds the index of the column each Edge field is read
+// from, as given by the header row of a CSV file or LoadOptions.Fields.
+// Any other named columns are read as attributes: those named after
+// an endpoint column, e.g. "source.label", as attributes of that
+// vertex and the rest as attributes of the edge. An attribute name
+// may end in a type, e.g. "zip:string", see attributeTypes.
+type csvColumns struct {
+	fields           map[string]int            // [Field]Column
+	edgeAttributes   map[string]int            // [Attribute]Column
+	vertexAttributes map[string]map[string]int // [Field of the endpoint][Attribute]Column
+	types            map[int]string            // [Column]Type of the attribute, if given
+	width            int                       // Exact number of values per row, if not 0
+	weightless       *csvColumns               // Used instead for rows of its width, if not nil
+}
+
+func newCSVColumns() *csvColumns {
+	return &csvColumns{
+		fields:         make(map[string]int),
+		edgeAttributes: make(map[string]int),
+		types:          make(map[int]string),
+		vertexAttributes: map[string]map[string]int{
+			SourceField: make(map[string]int),
+			TargetField: make(map[string]int),
+		},
+	}
+}
+
+// hasEndpoints reports whether both the source and the target
+// column are known.
+func (c *csvColumns) hasEndpoints() bool {
+	_, hasSource := c.fields[SourceField]
+	_, hasTarget := c.fields[TargetField]
+	return hasSource && hasTarget
+}
+
+// parseCSVHeader checks whether record is a header row naming the
+// columns of the file, using the given header names. It is considered
+// one if it names both endpoints of the edges.
+func parseCSVHeader(record []string, names map[string]string) (*csvColumns, bool) {
+	columns := newCSVColumns()
+	for i, name := range record {
+		name = strings.TrimSpace(name)
+		if field, ok := names[strings.ToLower(name)]; ok {
+			if _, seen := columns.fields[field]; !seen {
+				columns.fields[field] = i
+			}
+			continue
+		}
+		if name == "" {
+			continue
+		}
+
+		name, typ := attributeType(name)
+		if typ != "" {
+			columns.types[i] = typ
+		}
+		prefix, attribute, found := strings.Cut(name, ".")
+		if field := names[strings.ToLower(prefix)]; found && (field == SourceField || field == TargetField) {
+			columns.vertexAttributes[field][attribute] = i
+		} else {
+			columns.edgeAttributes[name] = i
+		}
+	}
+
+	if !columns.hasEndpoints() {
+		return nil, false
+	}
+
+	return columns, true
+}
+
+// csvEdge builds an edge from record according to the columns. Missing
+// numeric columns are left at zero, except for the weight which
+// defaults to -1 like for weightless files, and the capacity which
+// is the weight if there is a weight column. It fails if the record
+// has the wrong number of values or a numeric value can not be parsed
+// as a W.
+func csvEdge[W Number](c *csvColumns, record []string) (EdgeOf[string, W], *ParseError) {
+	if c.weightless != nil && len(record) == c.weightless.width {
+		return csvEdge[W](c.weightless, record)
+	}
+	if c.width > 0 && len(record) != c.width {
+		if c.weightless != nil {
+			return EdgeOf[string, W]{}, rowError(0, "expected %d or %d values, got %d", c.weightless.width, c.width, len(record))
+		}
+		return EdgeOf[string, W]{}, rowError(0, "expected %d values, got %d", c.width, len(record))
+	}
+	for _, i := range c.fields {
+		if i >= len(record) {
+			return EdgeOf[string, W]{}, rowError(0, "expected at least %d values, got %d", i+1, len(record))
+		}
+	}
+
+	e := EdgeOf[string, W]{
+		Start:  Vertex{ID: record[c.fields[SourceField]]},
+		End:    Vertex{ID: record[c.fields[TargetField]]},
+		Weight: -1,
+	}
+
+	for _, name := range []string{WeightAttribute, CapacityAttribute, CostAttribute} {
+		i, ok := c.fields[name]
+		if !ok {
+			continue
+		}
+		value, err := parseNumber[W](record[i])
+		if err != nil {
+			return EdgeOf[string, W]{}, rowError(i+1, "invalid %s %q", name, record[i])
+		}
+		e.SetAttribute(name, value)
+	}
+	if _, ok := c.fields[CapacityAttribute]; !ok {
+		// Files from before capacities were read separately
+		// use the weight as the capacity
+		if _, ok := c.fields[WeightAttribute]; ok {
+			e.Capacity = e.Weight
+		}
+	}
+
+	if i, ok := c.fields[IDField]; ok {
+		e.ID = record[i]
+	}
+
+	return e, nil
+}
+
+// attributeTypes are the types an attribute column can be given by
+// ending its name in ":" and the type, e.g. "zip:string". The values
+// of such columns are read as that type, instead of the most specific
+// one ParseAttribute finds.
+var attributeTypes = map[string]bool{"string": true, "int": true, "float": true, "bool": true}
+
+// attributeType splits the type off an attribute column name,
+// returning "" for the type if there is none.
+func attributeType(name string) (string, string) {
+	if i := strings.LastIndex(name, ":"); i >= 0 && attributeTypes[name[i+1:]] {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
+
+// parseTypedAttribute parses an attribute value of the given type,
+// see attributeTypes, or any type if typ is "".
+func parseTypedAttribute(s, typ string) (any, error) {
+	switch typ {
+	case "string":
+		return s, nil
+	case "int":
+		return strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	case "float":
+		return strconv.ParseFloat(strings.TrimSpace(s), 64)
+	case "bool":
+		return strconv.ParseBool(strings.TrimSpace(s))
+	}
+	return ParseAttribute(s), nil
+}
+
+// attributes reads the attributes of the edge and of its source and
+// target from record. Empty values are left out. It fails if a value
+// does not have the type given for its column.
+func (c *csvColumns) attributes(record []string) (edge, source, target Attributes, rowErr *ParseError) {
+	read := func(columns map[string]int) Attributes {
+		attributes := make(Attributes)
+		for name, i := range columns {
+			if i >= len(record) || strings.TrimSpace(record[i]) == "" || rowErr != nil {
+				continue
+			}
+			value, err := parseTypedAttribute(record[i], c.types[i])
+			if err != nil {
+				rowErr = rowError(i+1, "invalid %s %s %q", c.types[i], name, record[i])
+				continue
+			}
+			attributes[name] = value
+		}
+		return attributes
+	}
+
+	edge = read(c.edgeAttributes)
+	source = read(c.vertexAttributes[SourceField])
+	target = read(c.vertexAttributes[TargetField])
+	return edge, source, target, rowErr
+}
+
+// readCSV reads edges from CSV data. If the first row is a header
+// the values are read from the named columns, otherwise from the
+// columns given by opts.Fields. Without an id column the edges are
+// numbered.
+func (gr *graphReader[W]) readCSV(r io.Reader) error {
+	names, err := gr.opts.headerNames()
+	if err != nil {
+		return err
+	}
+	// Used unless the file starts with a header row
+	columns, err := gr.opts.positionalColumns()
+	if err != nil {
+		return err
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = gr.opts.Separator
+	if reader.Comma == 0 {
+		reader.Comma = '\t'
+	}
+	reader.FieldsPerRecord = -1 // Checked row by row below
+
+	// options are available at:
+	// http://golang.org/src/pkg/encoding/csv/reader.go?s=3213:3671#L94
+	for firstRow := true; ; firstRow = false {
+		// read just one record, but we could ReadAll() as well
+		record, err := reader.Read()
+		// EOF is fitted into err
+		if err == io.EOF {
+			break
+		}
+		var csvErr *csv.ParseError
+		if errors.As(err, &csvErr) {
+			// Malformed quoting, the reader carries on with the next line
+			if err := gr.skip(&ParseError{Line: csvErr.Line, Reason: csvErr.Err.Error()}); err != nil {
+				return err
+			}
+			continue
+		} else if err != nil {
+			return err
+		}
+		line, _ := reader.FieldPos(0)
+
+		if firstRow {
+			if header, ok := parseCSVHeader(record, names); ok {
+				columns = header
+				continue
+			}
+		}
+
+		// Dig out the values, skip the row if invalid values
+		e, rowErr := csvEdge[W](columns, record)
+		var edgeAttributes, sourceAttributes, targetAttributes Attributes
+		if rowErr == nil {
+			edgeAttributes, sourceAttributes, targetAttributes, rowErr = columns.attributes(record)
+		}
+		if rowErr != nil {
+			rowErr.Line = line
+			if err := gr.skip(rowErr); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, ok := columns.fields[IDField]; !ok {
+			e.ID = gr.nextID()
+		}
+
+		gr.g.AddEdge(e)
+		gr.setEdgeAttributes(e, edgeAttributes)
+		gr.setVertexAttributes(e.Start, sourceAttributes)
+		gr.setVertexAttributes(e.End, targetAttributes)
+	}
+
+	return nil
+}
+
+// WriteCSV writes the edges of g to w as tab separated values, in the
+// format read by ReadDirectedGraph and ReadUndirectedGraph: a header row
+// followed by one row per edge with its endpoints, numeric fields, ID
+// and attributes. Vertex attributes are repeated on every row of the
+// edges of the vertex. Isolated vertices are left out, as the format
+// has no way of describing them.
+//
+// Attributes holding strings which would be read back as another type,
+// such as "01234", are written in columns typed as strings, e.g.
+// "zip:string", as long as the attribute only ever holds strings.
+// Empty strings are read back as missing.
+func WriteCSV(w io.Writer, g Graph) error {
+	return WriteCSVOf[int64](w, g)
+}
+
+// WriteCSVOf writes the edges of g to w like WriteCSV, for graphs of
+// any weight type. The numeric fields are written so that
+// ReadDirectedGraphOf and ReadUndirectedGraphOf read them back as W.
+func WriteCSVOf[W Number](w io.Writer, g GraphOf[string, W]) error {
+	vertexNames, edgeNames := attributeNames(g)
+
+	stringVertexNames := make(map[string]bool)
+	for _, v := range g.Vertices() {
+		markStringAttributes(stringVertexNames, g.VertexAttributes(v))
+	}
+	stringEdgeNames := make(map[string]bool)
+	for _, e := range g.Edges() {
+		markStringAttributes(stringEdgeNames, g.EdgeAttributes(e))
+	}
+	columnName := func(name string, stringNames map[string]bool) string {
+		if stringNames[name] {
+			return name + ":string"
+		}
+		return name
+	}
+
+	header := []string{"Vertex1", "Vertex2", WeightAttribute, CapacityAttribute, CostAttribute, "id"}
+	for _, endpoint := range []string{"Vertex1", "Vertex2"} {
+		for _, name := range vertexNames {
+			header = append(header, endpoint+"."+columnName(name, stringVertexNames))
+		}
+	}
+	for _, name := range edgeNames {
+		header = append(header, columnName(name, stringEdgeNames))
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = '\t'
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, e := range g.Edges() {
+		record := []string{
+			e.Start.ID,
+			e.End.ID,
+			formatNumber(e.Weight),
+			formatNumber(e.Capacity),
+			formatNumber(e.Cost),
+			e.ID,
+		}
+		for _, v := range []Vertex{e.Start, e.End} {
+			record = appendAttributes(record, g.VertexAttributes(v), vertexNames)
+		}
+		record = appendAttributes(record, g.EdgeAttributes(e), edgeNames)
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// markStringAttributes updates names, the attributes which need
+// their column typed as strings, with the given attributes. Those
+// are the attributes holding strings which ParseAttribute reads as
+// something else, unless they also hold values of other types.
+func markStringAttributes(names map[string]bool, attributes Attributes) {
+	for name, value := range attributes {
+		s, isString := value.(string)
+		if needed, seen := names[name]; seen && !needed {
+			continue
+		}
+		switch {
+		case !isString:
+			names[name] = false // Mixed types, the column can not be typed
+		case ParseAttribute(s) != s:
+			names[name] = true
+		}
+	}
+}
+
+// appendAttributes appends the formatted values of the named
+// attributes to record, leaving missing ones empty.
+func appendAttributes(record []string, attributes Attributes, names []string) []string {
+	for _, name := range names {
+		value, ok := attributes[name]
+		if !ok {
+			record = append(record, "")
+			continue
+		}
+		record = append(record, FormatAttribute(value))
+	}
+	return record
+}
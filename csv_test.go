@@ -0,0 +1,247 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadCapacityAndCost(t *testing.T) {
+	tests := []struct {
+		name                   string
+		csv                    string
+		weight, capacity, cost int64
+	}{
+		{"capacity column", "Vertex1\tVertex2\tcapacity\nx\ty\t5\n", -1, 5, 0},
+		{"weight and capacity", "Vertex1\tVertex2\tweight\tcapacity\tcost\nx\ty\t2\t5\t3\n", 2, 5, 3},
+		{"weight as capacity", "Vertex1\tVertex2\tweight\nx\ty\t2\n", 2, 2, 0},
+		{"headerless weight as capacity", "x\ty\t2\te1\n", 2, 2, 0},
+		{"no weight or capacity", "Vertex1\tVertex2\nx\ty\n", -1, 0, 0},
+	}
+
+	for _, test := range tests {
+		g, _, err := ReadDirectedGraph(strings.NewReader(test.csv), LoadOptions{Strict: true})
+		if err != nil {
+			t.Errorf("%s: ReadDirectedGraph failed: %s", test.name, err)
+			continue
+		}
+		edges := g.Edges()
+		if len(edges) != 1 {
+			t.Errorf("%s: read %d edges, want 1", test.name, len(edges))
+			continue
+		}
+		e := edges[0]
+		if e.Weight != test.weight || e.Capacity != test.capacity || e.Cost != test.cost {
+			t.Errorf("%s: weight, capacity and cost are %d, %d, %d, want %d, %d, %d", test.name, e.Weight, e.Capacity, e.Cost, test.weight, test.capacity, test.cost)
+		}
+	}
+}
+
+func TestReadStrict(t *testing.T) {
+	tests := []struct {
+		name   string
+		csv    string
+		edges  int // Edges read when not strict
+		line   int
+		column int
+		reason string
+	}{
+		{"invalid weight", "Vertex1\tVertex2\tweight\nx\ty\t2\nx\ty\tabc\n", 1, 3, 3, `invalid weight "abc"`},
+		{"too few values", "x\ty\t2\te1\nx\n", 1, 2, 0, "expected 3 or 4 values, got 1"},
+		{"too many values", "x\ty\t2\te1\nx\ty\t2\te1\tz\n", 1, 2, 0, "expected 3 or 4 values, got 5"},
+		{"invalid capacity", "Vertex1\tVertex2\tcapacity\nx\ty\t1.5\nx\tz\t1\n", 1, 2, 3, `invalid capacity "1.5"`},
+		{"broken quotes", "Vertex1\tVertex2\tweight\n\"x\ty\t2\n", 0, 2, 0, `extraneous or missing " in quoted-field`},
+	}
+
+	for _, test := range tests {
+		g, warnings, err := ReadDirectedGraph(strings.NewReader(test.csv), LoadOptions{})
+		if err != nil {
+			t.Errorf("%s: ReadDirectedGraph failed: %s", test.name, err)
+		} else if g.EdgeCount() != test.edges || len(warnings) != 1 {
+			t.Errorf("%s: read %d edges with warnings %v, want %d edges and one warning", test.name, g.EdgeCount(), warnings, test.edges)
+		}
+
+		_, _, err = ReadDirectedGraph(strings.NewReader(test.csv), LoadOptions{Strict: true})
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s: strict ReadDirectedGraph returned %v, want a *ParseError", test.name, err)
+			continue
+		}
+		if parseErr.Line != test.line || parseErr.Column != test.column || parseErr.Reason != test.reason {
+			t.Errorf("%s: got error at line %d, column %d: %s, want line %d, column %d: %s", test.name, parseErr.Line, parseErr.Column, parseErr.Reason, test.line, test.column, test.reason)
+		}
+	}
+}
+
+func TestLoadStrictFileName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.csv")
+	if err := os.WriteFile(path, []byte("x\ty\t2\te1\nx\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err := LoadUndirectedGraph(path, LoadOptions{Strict: true})
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.File != path {
+		t.Fatalf("LoadUndirectedGraph returned %v, want a *ParseError for %s", err, path)
+	}
+	if want := "graph: " + path + ":2: expected 3 or 4 values, got 1"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestParseErrorString(t *testing.T) {
+	tests := []struct {
+		err  ParseError
+		want string
+	}{
+		{ParseError{File: "a.csv", Line: 3, Column: 2, Reason: "bad"}, "graph: a.csv:3: column 2: bad"},
+		{ParseError{File: "a.csv", Line: 3, Reason: "bad"}, "graph: a.csv:3: bad"},
+		{ParseError{File: "a.csv", Reason: "bad"}, "graph: a.csv: bad"},
+		{ParseError{Line: 3, Column: 1, Reason: "bad"}, "graph: line 3: column 1: bad"},
+	}
+
+	for _, test := range tests {
+		if got := test.err.Error(); got != test.want {
+			t.Errorf("Error() = %q, want %q", got, test.want)
+		}
+	}
+}
+
+func TestReadColumns(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		opts LoadOptions
+		want string // The edge read, as "source target weight id"
+	}{
+		{"default header", "Vertex1\tVertex2\tweight\tid\nx\ty\t2\te\n", LoadOptions{}, "x y 2 e"},
+		{"reordered header", "id\tweight\tTo\tFrom\ne\t2\ty\tx\n", LoadOptions{}, "x y 2 e"},
+		{"quoted header", "\"Vertex1\"\t\"Vertex2\"\t\"id\"\n\"x\"\t\"y\"\t\"e\"\n", LoadOptions{}, "x y -1 e"},
+		{"custom header", "Head\tTail\tCost Of Edge\nx\ty\t2\n", LoadOptions{Columns: map[string]string{"head": SourceField, "TAIL": TargetField, "cost of edge": WeightAttribute}}, "x y 2 e1"},
+		{"headerless", "x\ty\t2\te\n", LoadOptions{}, "x y 2 e"},
+		{"headerless weightless", "x\ty\te\n", LoadOptions{}, "x y -1 e"},
+		{"headerless fields", "e\tx\ty\n", LoadOptions{Fields: []string{IDField, SourceField, TargetField}}, "x y -1 e"},
+		{"comma separated", "Vertex1,Vertex2,weight\nx,y,2\n", LoadOptions{Separator: ','}, "x y 2 e1"},
+	}
+
+	for _, test := range tests {
+		test.opts.Strict = true
+		g, _, err := ReadDirectedGraph(strings.NewReader(test.csv), test.opts)
+		if err != nil {
+			t.Errorf("%s: ReadDirectedGraph failed: %s", test.name, err)
+			continue
+		}
+		edges := g.Edges()
+		if len(edges) != 1 {
+			t.Errorf("%s: read %d edges, want 1", test.name, len(edges))
+			continue
+		}
+		e := edges[0]
+		if got := fmt.Sprintf("%s %s %d %s", e.Start.ID, e.End.ID, e.Weight, e.ID); got != test.want {
+			t.Errorf("%s: read %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestReadColumnsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts LoadOptions
+	}{
+		{"unknown column field", LoadOptions{Columns: map[string]string{"a": "colour"}}},
+		{"unknown field", LoadOptions{Fields: []string{SourceField, TargetField, "colour"}}},
+		{"field given twice", LoadOptions{Fields: []string{SourceField, TargetField, TargetField}}},
+		{"missing target", LoadOptions{Fields: []string{SourceField, WeightAttribute}}},
+	}
+
+	for _, test := range tests {
+		if _, _, err := ReadDirectedGraph(strings.NewReader("x\ty\t1\n"), test.opts); err == nil {
+			t.Errorf("%s: ReadDirectedGraph succeeded", test.name)
+		}
+	}
+}
+
+// TestLoadCSVFiles loads every file in csv_files, all of which
+// must be read without skipping any rows.
+func TestLoadCSVFiles(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("csv_files", "*.csv"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no csv_files found: %v", err)
+	}
+
+	for _, path := range paths {
+		g, warnings, err := LoadUndirectedGraph(path, LoadOptions{})
+		if err != nil {
+			t.Errorf("loading %s failed: %s", path, err)
+			continue
+		}
+		if len(warnings) > 0 || g.EdgeCount() == 0 {
+			t.Errorf("%s: read %d edges with warnings %v", path, g.EdgeCount(), warnings)
+		}
+	}
+}
+
+// buildAttributeGraph fills g with a few edges and attributes of
+// every type, including strings which look like other types.
+func buildAttributeGraph(g graphBuilder[int64]) {
+	a, b, c := Vertex{ID: "a"}, Vertex{ID: "b b"}, Vertex{ID: "c"}
+	ab := Edge{Start: a, End: b, Weight: 2, Capacity: 3, ID: "007"}
+	bc := Edge{Start: b, End: c, Weight: -1, Cost: 4, ID: "e2"}
+	g.AddEdge(ab)
+	g.AddEdge(bc)
+	g.AddVertex(Vertex{ID: "isolated"})
+
+	g.SetVertexAttribute(a, "zip", "01234")
+	g.SetVertexAttribute(a, "x", 1.5)
+	g.SetVertexAttribute(c, "x", 2.0)
+	g.SetVertexAttribute(c, "seen", true)
+	g.SetEdgeAttribute(ab, "label", `x "y", z`)
+	g.SetEdgeAttribute(bc, "label", "true")
+	g.SetEdgeAttribute(ab, "rank", int64(1))
+}
+
+// describeGraph lists the vertices and edges of g with their
+// attributes, for comparing graphs.
+func describeGraph(g Graph) string {
+	var b strings.Builder
+	for _, v := range g.Vertices() {
+		fmt.Fprintf(&b, "%s %v\n", v.ID, g.VertexAttributes(v))
+	}
+	for _, e := range g.Edges() {
+		fmt.Fprintf(&b, "%+v %v\n", e, g.EdgeAttributes(e))
+	}
+	return b.String()
+}
+
+func TestWriteCSVRoundTrip(t *testing.T) {
+	directed, undirected := &DirectedGraph{}, &UndirectedGraph{}
+	buildAttributeGraph(directed)
+	buildAttributeGraph(undirected)
+
+	for _, g := range []Graph{directed, undirected} {
+		var b strings.Builder
+		if err := WriteCSV(&b, g); err != nil {
+			t.Fatalf("WriteCSV failed: %s", err)
+		}
+
+		var read Graph
+		var err error
+		if isDirected(g) {
+			read, _, err = ReadDirectedGraph(strings.NewReader(b.String()), LoadOptions{Strict: true})
+		} else {
+			read, _, err = ReadUndirectedGraph(strings.NewReader(b.String()), LoadOptions{Strict: true})
+		}
+		if err != nil {
+			t.Fatalf("reading back\n%s\nfailed: %s", b.String(), err)
+		}
+
+		// CSV has no rows for isolated vertices
+		want := strings.Replace(describeGraph(g), "isolated map[]\n", "", 1)
+		if got := describeGraph(read); got != want {
+			t.Errorf("read back\n%s\nas\n%s\nwant\n%s", b.String(), got, want)
+		}
+	}
+}
@@ -1,276 +1,408 @@
-package main
+package graph
 
 import (
-	"encoding/csv"
 	"fmt"
 	"io"
-	"math"
 	"os"
-	"strconv"
+	"slices"
 )
 
-type DirectedGraph struct {
-	edges    map[Vertex][]Edge // [Start]Edges
-	vertices []Vertex
+// DirectedGraphOf is a weighted graph where every edge
+// leads from its Start vertex to its End vertex.
+//
+// The zero value is an empty graph in which AddEdge only drops edges
+// identical to one already in the graph. A graph made by
+// NewDirectedMultigraph instead identifies edges by their ID, see
+// IsMultigraph.
+type DirectedGraphOf[K comparable, W Number] struct {
+	edges      map[VertexOf[K]][]EdgeOf[K, W] // [Start]Edges
+	inEdges    map[VertexOf[K]][]EdgeOf[K, W] // [End]Edges
+	vertices   []VertexOf[K]
+	index      map[VertexOf[K]]int       // [Vertex]Position in vertices
+	edgeSet    map[EdgeOf[K, W]]bool     // Every edge in edges, for quick lookups
+	edgesByID  map[string][]EdgeOf[K, W] // [ID]Edges
+	multigraph bool
+
+	vertexAttributes map[VertexOf[K]]Attributes
+	edgeAttributes   map[EdgeOf[K, W]]Attributes
 }
 
-func (d *DirectedGraph) VertexCount() int {
+// DirectedGraph is a directed graph with string
+// vertex IDs and int64 weights.
+type DirectedGraph = DirectedGraphOf[string, int64]
+
+// NewDirectedMultigraph returns an empty directed multigraph.
+func NewDirectedMultigraph() *DirectedGraph {
+	return NewDirectedMultigraphOf[string, int64]()
+}
+
+// NewDirectedMultigraphOf returns an empty directed multigraph.
+func NewDirectedMultigraphOf[K comparable, W Number]() *DirectedGraphOf[K, W] {
+	return &DirectedGraphOf[K, W]{multigraph: true}
+}
+
+// IsMultigraph reports whether the graph is a multigraph. In a multigraph
+// edges are identified by their ID: any number of parallel edges may
+// connect the same vertices as long as their IDs differ, while adding
+// an edge with the ID of an existing edge has no effect. Edges without
+// an ID are only dropped if identical to an existing edge.
+//
+// Graphs which are not multigraphs keep parallel edges too, as long as
+// they differ in their ID or another field: only an edge identical to
+// one already in the graph is dropped.
+func (d *DirectedGraphOf[K, W]) IsMultigraph() bool {
+	return d.multigraph
+}
+
+// Vertices returns the vertices of the graph in insertion order.
+// The returned slice must not be modified. It keeps the vertices
+// it has when vertices are removed from the graph.
+func (d *DirectedGraphOf[K, W]) Vertices() []VertexOf[K] {
+	return d.vertices
+}
+
+// HasVertex reports whether v is part of the graph.
+func (d *DirectedGraphOf[K, W]) HasVertex(v VertexOf[K]) bool {
+	_, ok := d.index[v]
+	return ok
+}
+
+func (d *DirectedGraphOf[K, W]) VertexCount() int {
 	return len(d.vertices)
 }
 
-func (d *DirectedGraph) EdgeCount() int {
-	return len(d.edges)
+// EdgeCount returns the number of edges in the graph.
+func (d *DirectedGraphOf[K, W]) EdgeCount() int {
+	return len(d.edgeSet)
 }
 
-func (d *DirectedGraph) String() string {
+// Edges returns every edge of the graph, grouped by start
+// vertex in the order the vertices were added.
+func (d *DirectedGraphOf[K, W]) Edges() []EdgeOf[K, W] {
+	edges := make([]EdgeOf[K, W], 0, len(d.edgeSet))
+	for _, v := range d.vertices {
+		edges = append(edges, d.edges[v]...)
+	}
+
+	return edges
+}
+
+// OutEdges returns the edges starting at v.
+// The returned slice must not be modified.
+func (d *DirectedGraphOf[K, W]) OutEdges(v VertexOf[K]) []EdgeOf[K, W] {
+	return d.edges[v]
+}
+
+// InEdges returns the edges ending at v.
+// The returned slice must not be modified.
+func (d *DirectedGraphOf[K, W]) InEdges(v VertexOf[K]) []EdgeOf[K, W] {
+	return d.inEdges[v]
+}
+
+// Degree returns the total number of edges
+// starting or ending at v.
+func (d *DirectedGraphOf[K, W]) Degree(v VertexOf[K]) int {
+	return len(d.edges[v]) + len(d.inEdges[v])
+}
+
+func (d *DirectedGraphOf[K, W]) String() string {
 	s := "\n"
 	for k, v := range d.edges {
-		s += fmt.Sprintf("%s\n\t%v\n", k.id, v)
+		s += fmt.Sprintf("%v\n\t%v\n", k.ID, v)
 	}
 
 	return s
 }
 
-func (d *DirectedGraph) AddEdge(e Edge) {
+func (d *DirectedGraphOf[K, W]) AddEdge(e EdgeOf[K, W]) {
 	if d.edges == nil {
 		// Lazily initialize
-		d.edges = make(map[Vertex][]Edge)
+		d.edges = make(map[VertexOf[K]][]EdgeOf[K, W])
+		d.inEdges = make(map[VertexOf[K]][]EdgeOf[K, W])
+		d.edgeSet = make(map[EdgeOf[K, W]]bool)
+		d.edgesByID = make(map[string][]EdgeOf[K, W])
+	}
+
+	if d.multigraph && e.ID != "" && len(d.edgesByID[e.ID]) > 0 {
+		return // Already have an edge with this ID
 	}
 
-	edgeFound := false
+	// Add the edge, unless it is already in the graph
+	if !d.edgeSet[e] {
+		d.edgeSet[e] = true
+		d.edges[e.Start] = append(d.edges[e.Start], e)
+		d.inEdges[e.End] = append(d.inEdges[e.End], e)
+		d.edgesByID[e.ID] = append(d.edgesByID[e.ID], e)
+	}
 
-	if edges, ok := d.edges[e.start]; ok {
-		// Check that edge is not already in list
-		for _, existingEdge := range edges {
-			if existingEdge == e {
-				edgeFound = true
-			}
-		}
+	// Add the two vertices too, if they did not exist already
+	d.AddVertex(e.Start)
+	d.AddVertex(e.End)
+}
+
+// Grow makes room for the given numbers of vertices and edges to be
+// added, so that the graph does not have to grow step by step while
+// they are added, e.g. when the size of a file is known up front.
+func (d *DirectedGraphOf[K, W]) Grow(vertices, edges int) {
+	d.edges = growMap(d.edges, vertices)
+	d.inEdges = growMap(d.inEdges, vertices)
+	d.edgeSet = growMap(d.edgeSet, edges)
+	d.edgesByID = growMap(d.edgesByID, edges)
+	d.index = growMap(d.index, vertices)
+	d.vertices = slices.Grow(d.vertices, vertices)
+}
+
+// AddVertex adds v to the graph unless it is already part of it.
+// Vertices added this way may stay isolated, without any edges.
+func (d *DirectedGraphOf[K, W]) AddVertex(v VertexOf[K]) {
+	if d.index == nil {
+		// Lazily initialize
+		d.index = make(map[VertexOf[K]]int)
 	}
 
-	if edgeFound == false {
-		// Add the edge
-		d.edges[e.start] = append(d.edges[e.start], e)
+	if _, ok := d.index[v]; !ok {
+		d.index[v] = len(d.vertices)
+		d.vertices = append(d.vertices, v)
 	}
+}
 
-	// Check whether the two vertices already existed. If not, add them too
-	startExists := false
-	endExists := false
-	for _, existingVertex := range d.vertices {
-		if existingVertex == e.start {
-			startExists = true
-		} else if existingVertex == e.end {
-			endExists = true
-		}
+// RemoveEdge removes e from the graph, and reports whether it was
+// part of it. The vertices of the edge are left in the graph.
+func (d *DirectedGraphOf[K, W]) RemoveEdge(e EdgeOf[K, W]) bool {
+	if !d.edgeSet[e] {
+		return false
 	}
 
-	if startExists == false {
-		d.vertices = append(d.vertices, e.start)
+	delete(d.edgeSet, e)
+	d.edges[e.Start] = removeEdge(d.edges[e.Start], e)
+	if len(d.edges[e.Start]) == 0 {
+		delete(d.edges, e.Start)
 	}
-	if endExists == false {
-		d.vertices = append(d.vertices, e.end)
+	d.inEdges[e.End] = removeEdge(d.inEdges[e.End], e)
+	if len(d.inEdges[e.End]) == 0 {
+		delete(d.inEdges, e.End)
 	}
+	d.edgesByID[e.ID] = removeEdge(d.edgesByID[e.ID], e)
+	if len(d.edgesByID[e.ID]) == 0 {
+		delete(d.edgesByID, e.ID)
+	}
+	delete(d.edgeAttributes, e)
+
+	return true
 }
 
-// NewUndirectedGraphFromFile reads in a graph from the given path to a CSV.
-// It expects values in the form [startNodeID, endNodeID, weight, edgeID]
-// for every row. It will skip rows where the length is not 4 or the third
-// value can not be parsed as an integer.
-// It returns an UndirectedGraph, built from the values in the csv file
-// It will throw an error if the file can not be read for some reason.
-func NewDirectedGraphFromFile(filePath string, valueSeparator rune) (*DirectedGraph, error) {
-	g := DirectedGraph{}
+// EdgeByID returns the edge with the given ID, and false if there is
+// none. Outside of a multigraph several edges may share an ID, in
+// which case the first one added is returned.
+func (d *DirectedGraphOf[K, W]) EdgeByID(id string) (EdgeOf[K, W], bool) {
+	edges := d.edgesByID[id]
+	if len(edges) == 0 {
+		return EdgeOf[K, W]{}, false
+	}
 
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
+	return edges[0], true
+}
+
+// RemoveEdgeByID removes the edges with the given ID from the
+// graph, and reports whether there were any.
+func (d *DirectedGraphOf[K, W]) RemoveEdgeByID(id string) bool {
+	// Copy the edges, RemoveEdge modifies the slice
+	edges := append([]EdgeOf[K, W](nil), d.edgesByID[id]...)
+	for _, e := range edges {
+		d.RemoveEdge(e)
 	}
-	// automatically call Close() at the end of current method
-	defer file.Close()
 
-	reader := csv.NewReader(file)
-	reader.Comma = valueSeparator
-
-	// options are available at:
-	// http://golang.org/src/pkg/encoding/csv/reader.go?s=3213:3671#L94
-	for {
-		// read just one record, but we could ReadAll() as well
-		record, err := reader.Read()
-		// EOF is fitted into err
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			return nil, err
-		}
+	return len(edges) > 0
+}
 
-		// We're expecting four values
-		if len(record) != 4 {
-			continue
-		}
+// RemoveVertex removes v and every edge starting or ending at it
+// from the graph, and reports whether v was part of it.
+func (d *DirectedGraphOf[K, W]) RemoveVertex(v VertexOf[K]) bool {
+	i, ok := d.index[v]
+	if !ok {
+		return false
+	}
 
-		// Dig out the values, skip the row if invalid values
-		start := Vertex{id: record[0]}
-		end := Vertex{id: record[1]}
-		weight, err := strconv.ParseInt(record[2], 0, 64)
-		if err != nil {
-			// log.Printf("Skipping row '%v', not a valid edge!\n", record)
-			continue
-		}
-		id := record[3]
+	// Copy the edges, RemoveEdge modifies the slices
+	incident := append(append([]EdgeOf[K, W](nil), d.edges[v]...), d.inEdges[v]...)
+	for _, e := range incident {
+		d.RemoveEdge(e)
+	}
 
-		// Create & add Edge
-		e := Edge{start: start, end: end, weight: weight, id: id}
-		g.AddEdge(e)
+	// Remove the vertex, keeping the rest in insertion order. The
+	// slice is copied, as Vertices may have returned the old one.
+	delete(d.index, v)
+	delete(d.vertexAttributes, v)
+	d.vertices = append(d.vertices[:i:i], d.vertices[i+1:]...)
+	for j := i; j < len(d.vertices); j++ {
+		d.index[d.vertices[j]] = j
 	}
 
-	return &g, nil
+	return true
 }
 
-func smallestDistanceVertex(dists map[Vertex]int64, Q map[Vertex]bool) Vertex {
-	var smallestDistVertex Vertex
-	var smallestDist int64 = -1
+// VertexAttributes returns the attributes of v, or nil if it has none.
+// The returned map must not be modified, use SetVertexAttribute.
+func (d *DirectedGraphOf[K, W]) VertexAttributes(v VertexOf[K]) Attributes {
+	return d.vertexAttributes[v]
+}
 
-	for v, _ := range Q {
-		dist := dists[v]
-		if smallestDist == -1 || dist < smallestDist {
-			smallestDist = dist
-			smallestDistVertex = v
-		}
+// SetVertexAttribute sets the named attribute of v, and reports
+// false (leaving the graph unchanged) if v is not part of the graph.
+func (d *DirectedGraphOf[K, W]) SetVertexAttribute(v VertexOf[K], name string, value any) bool {
+	if !d.HasVertex(v) {
+		return false
 	}
-	return smallestDistVertex
+
+	setAttribute(&d.vertexAttributes, v, name, value)
+	return true
 }
 
-func (d *DirectedGraph) shortestPathsFrom(source Vertex) {
-	dists := make(map[Vertex]int64)
-	Q := make(map[Vertex]bool)
-	previousOptimalPathNode := make(map[Vertex]Vertex)
+// EdgeAttributes returns the attributes of e, or nil if it has none.
+// The returned map must not be modified, use SetEdgeAttribute.
+func (d *DirectedGraphOf[K, W]) EdgeAttributes(e EdgeOf[K, W]) Attributes {
+	return d.edgeAttributes[e]
+}
 
-	for _, vertex := range d.vertices {
-		Q[vertex] = true
-		dists[vertex] = math.MaxInt64
+// SetEdgeAttribute sets the named attribute of e, and reports
+// false (leaving the graph unchanged) if e is not part of the graph.
+func (d *DirectedGraphOf[K, W]) SetEdgeAttribute(e EdgeOf[K, W], name string, value any) bool {
+	if !d.edgeSet[e] {
+		return false
 	}
-	dists[source] = 0 // Dist to source is 0
 
-	for _, edge := range d.edges[source] {
-		// Dist to nearest neighbours is easy
-		dists[edge.end] = edge.weight
-	}
+	setAttribute(&d.edgeAttributes, e, name, value)
+	return true
+}
 
-	for len(Q) > 0 {
-		// Find vertex u in Q with smallest distance in dists[]
-		u := smallestDistanceVertex(dists, Q)
-		// Remove u from Q
-		delete(Q, u)
-		if dists[u] == math.MaxInt64 {
-			break // all remaining vertices are inaccessible from source
+// Subgraph returns the subgraph induced by the given vertices: those
+// of them which are part of the graph, and every edge between them.
+// Attributes are copied along, and the result is a multigraph if
+// the graph is one.
+func (d *DirectedGraphOf[K, W]) Subgraph(vertices []VertexOf[K]) *DirectedGraphOf[K, W] {
+	sub := &DirectedGraphOf[K, W]{multigraph: d.multigraph}
+	for _, v := range vertices {
+		if d.HasVertex(v) {
+			sub.addVertexFrom(d, v)
 		}
-
-		for _, v := range d.edges[u] {
-			// where v has not yet been removed from Q
-			if _, ok := Q[v.end]; ok {
-				alt := dists[u] + v.weight
-				if alt < dists[v.end] {
-					dists[v.end] = alt
-					previousOptimalPathNode[v.end] = u
-				}
+	}
+	for _, u := range d.vertices {
+		if !sub.HasVertex(u) {
+			continue
+		}
+		for _, e := range d.edges[u] {
+			if sub.HasVertex(e.End) {
+				sub.addEdgeFrom(d, e)
 			}
 		}
 	}
 
-	for v, dist := range dists {
-		if dist == math.MaxInt64 {
-			fmt.Printf("%s\t%s\tNo path!\n", source, v)
-		} else {
-			fmt.Printf("%s\t%s\t%d\n", source, v, dist)
+	return sub
+}
+
+// EdgeSubgraph returns the subgraph made up of the given edges which
+// are part of the graph, and their endpoints. Attributes are copied
+// along, and the result is a multigraph if the graph is one.
+func (d *DirectedGraphOf[K, W]) EdgeSubgraph(edges []EdgeOf[K, W]) *DirectedGraphOf[K, W] {
+	sub := &DirectedGraphOf[K, W]{multigraph: d.multigraph}
+	for _, e := range edges {
+		if d.edgeSet[e] {
+			sub.addVertexFrom(d, e.Start)
+			sub.addVertexFrom(d, e.End)
+			sub.addEdgeFrom(d, e)
 		}
 	}
-}
 
-func (d *DirectedGraph) findPathWithFlow(source, sink Vertex, usedCapacity, maxCapacity map[Edge]int64) Edges {
-	parentPath := make(map[Vertex]Edge)
+	return sub
+}
 
-	alreadyAddedToStack := make(map[Vertex]bool)
-	for _, vertex := range d.vertices {
-		alreadyAddedToStack[vertex] = false
+// addVertexFrom adds v to the graph together with its attributes in g.
+func (d *DirectedGraphOf[K, W]) addVertexFrom(g *DirectedGraphOf[K, W], v VertexOf[K]) {
+	d.AddVertex(v)
+	for name, value := range g.vertexAttributes[v] {
+		d.SetVertexAttribute(v, name, value)
 	}
+}
 
-	vStack := VertexStack{}
-	vStack.Push(source)
-	alreadyAddedToStack[source] = true
-
-	for vStack.Len() > 0 {
-		// Pop the top node
-		v := vStack.Pop()
-		// Is it the sink?
-		if v == sink {
-			// If so, backtrace the path and return it.
-			var reversePath Edges
-
-			for {
-				step, ok := parentPath[v]
-
-				if !ok { // We reached the source
-					//fmt.Printf("No parent path for vertex %s\n", v)
-					return reversePath // Return the path (order does not matter)
-				} else {
-					//fmt.Printf("Adding path from %s to %s to paths\n", step.start.id, step.end.id)
-					reversePath = append(reversePath, step) // Add the last step
-					v = step.start                          // Go to the parent vertex.
-				}
-			}
-		} else {
-			for _, edge := range d.edges[v] {
-				// Add all the node's children (not already added)
-				// with free capacity to the queue.
-				if alreadyAddedToStack[edge.end] == false {
-					if maxCapacity[edge]-usedCapacity[edge] > 0 {
-						vStack.Push(edge.end)
-						alreadyAddedToStack[edge.end] = true
-						parentPath[edge.end] = edge
-					}
-				}
-			}
-		}
+// addEdgeFrom adds e to the graph together with its attributes in g.
+func (d *DirectedGraphOf[K, W]) addEdgeFrom(g *DirectedGraphOf[K, W], e EdgeOf[K, W]) {
+	d.AddEdge(e)
+	for name, value := range g.edgeAttributes[e] {
+		d.SetEdgeAttribute(e, name, value)
 	}
+}
 
-	return nil // No path with free capacity left.
+// ReadDirectedGraph reads in a graph from r, in the format given by
+// opts.Format. For CSV, the default, it expects values in the form
+// [startNodeID, endNodeID, weight, edgeID] for every row, or
+// [startNodeID, endNodeID, edgeID] for graphs without weights,
+// unless opts.Fields says otherwise.
+// If the first row is a header naming the columns (e.g. "Vertex1",
+// "Vertex2", "capacity", "id"), the values are instead read from the
+// named columns into the matching Edge fields, see LoadOptions.Columns.
+// Without a capacity column the weight is used as the capacity.
+// Without an id column the edges are numbered "e1", "e2" and so on.
+// Other named columns are kept as attributes of the edge, or of its
+// start or end vertex if named e.g. "Vertex1.label" or "Vertex2.x".
+//
+// The other formats are described with their Format constants.
+// Input of any format is decompressed if it is gzip compressed.
+// Edges are added in the direction they are written, even if the
+// input describes an undirected graph.
+//
+// Rows which are not valid edges make it fail with a *ParseError in
+// strict mode, and are otherwise skipped and returned as warnings.
+// It will also return an error if r can not be read.
+func ReadDirectedGraph(r io.Reader, opts LoadOptions) (*DirectedGraph, []*ParseError, error) {
+	return ReadDirectedGraphOf[int64](r, opts)
 }
 
-func (d *DirectedGraph) pathWithFlowExists(source, sink Vertex, usedCapacity, maxCapacity map[Edge]int64) bool {
-	path := d.findPathWithFlow(source, sink, usedCapacity, maxCapacity)
-	if len(path) > 0 {
-		return true
+// ReadDirectedGraphOf reads in a graph like ReadDirectedGraph, parsing its
+// weights, capacities and costs as W.
+func ReadDirectedGraphOf[W Number](r io.Reader, opts LoadOptions) (*DirectedGraphOf[string, W], []*ParseError, error) {
+	g := DirectedGraphOf[string, W]{multigraph: opts.Multigraph}
+	warnings, err := readGraph[W](&g, r, "", opts)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return false
+	return &g, warnings, nil
 }
 
-func (d *DirectedGraph) FindMaxFlow(source, sink Vertex) (map[Edge]int64, int) {
-	// Track max flow
-	maxFlow := 0
-	// Set usedCapacity for all edges to zero
-	usedCapacity := make(map[Edge]int64)
-	// TODO: Fix dirty hack - we use the weight as the flow value.
-	maxCapacity := make(map[Edge]int64)
-	for _, edges := range d.edges {
-		for _, e := range edges {
-			usedCapacity[e] = 0
-			maxCapacity[e] = e.weight
-		}
+// LoadDirectedGraph reads in a graph from the file at filePath, in the format
+// given by its extension unless opts.Format is set, see ReadDirectedGraph.
+func LoadDirectedGraph(filePath string, opts LoadOptions) (*DirectedGraph, []*ParseError, error) {
+	return LoadDirectedGraphOf[int64](filePath, opts)
+}
+
+// LoadDirectedGraphOf reads in a graph like LoadDirectedGraph, parsing its
+// weights, capacities and costs as W.
+func LoadDirectedGraphOf[W Number](filePath string, opts LoadOptions) (*DirectedGraphOf[string, W], []*ParseError, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, err
 	}
+	// automatically call Close() at the end of current method
+	defer file.Close()
 
-	for {
-		// While there is a path with free capacity - look for paths
-		path := d.findPathWithFlow(source, sink, usedCapacity, maxCapacity)
-		if len(path) == 0 {
-			break
-		}
-		// When we find a path, send 1 unit of "flow" along it's path.
-		for _, edge := range path {
-			usedCapacity[edge] += 1
-		}
-		maxFlow += 1
+	if opts.Format == "" {
+		opts.Format, _ = FormatFromPath(filePath)
 	}
 
-	return usedCapacity, maxFlow
+	g := DirectedGraphOf[string, W]{multigraph: opts.Multigraph}
+	warnings, err := readGraph[W](&g, file, filePath, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &g, warnings, nil
+}
+
+// NewDirectedGraphFromFile reads in a graph from the given path to a CSV,
+// skipping rows which are not valid edges. See ReadDirectedGraph.
+// It returns a DirectedGraph, built from the values in the csv file
+// It will throw an error if the file can not be read for some reason.
+func NewDirectedGraphFromFile(filePath string, valueSeparator rune) (*DirectedGraph, error) {
+	g, _, err := LoadDirectedGraph(filePath, LoadOptions{Separator: valueSeparator})
+	return g, err
 }
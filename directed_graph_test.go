@@ -0,0 +1,210 @@
+package graph
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// testEdges returns n edges between about n/4 vertices, with some
+// vertices having many edges, in a fixed order.
+func testEdges(n int) []Edge {
+	vertices := n/4 + 2
+	edges := make([]Edge, n)
+	for i := range edges {
+		start, end := i%vertices, (i*7919+i/vertices)%vertices
+		edges[i] = Edge{
+			Start:  Vertex{ID: "n" + strconv.Itoa(start)},
+			End:    Vertex{ID: "n" + strconv.Itoa(end)},
+			Weight: int64(i % 10),
+			ID:     "e" + strconv.Itoa(i),
+		}
+	}
+	return edges
+}
+
+// testCSV returns the edges as a CSV file with a header row.
+func testCSV(edges []Edge) []byte {
+	var b bytes.Buffer
+	b.WriteString("Vertex1\tVertex2\tweight\tid\n")
+	for _, e := range edges {
+		fmt.Fprintf(&b, "%s\t%s\t%d\t%s\n", e.Start.ID, e.End.ID, e.Weight, e.ID)
+	}
+	return b.Bytes()
+}
+
+func TestDirectedGraphAddEdge(t *testing.T) {
+	a, b, c := Vertex{ID: "a"}, Vertex{ID: "b"}, Vertex{ID: "c"}
+
+	tests := []struct {
+		name     string
+		edges    []Edge
+		vertices []Vertex
+		count    int // Expected EdgeCount
+	}{
+		{"single", []Edge{{Start: a, End: b, ID: "1"}}, []Vertex{a, b}, 1},
+		{"identical edges are dropped", []Edge{{Start: a, End: b, ID: "1"}, {Start: a, End: b, ID: "1"}}, []Vertex{a, b}, 1},
+		{"reverse is a different edge", []Edge{{Start: a, End: b, ID: "1"}, {Start: b, End: a, ID: "1"}}, []Vertex{a, b}, 2},
+		{"parallel edges are kept", []Edge{{Start: a, End: b, ID: "1"}, {Start: a, End: b, ID: "2"}}, []Vertex{a, b}, 2},
+		{"vertices in insertion order", []Edge{{Start: c, End: a}, {Start: b, End: c}}, []Vertex{c, a, b}, 2},
+		{"loop", []Edge{{Start: a, End: a}}, []Vertex{a}, 1},
+	}
+
+	for _, test := range tests {
+		g := &DirectedGraph{}
+		for _, e := range test.edges {
+			g.AddEdge(e)
+		}
+		if got := g.EdgeCount(); got != test.count {
+			t.Errorf("%s: EdgeCount = %d, want %d", test.name, got, test.count)
+		}
+		if got := g.Vertices(); fmt.Sprint(got) != fmt.Sprint(test.vertices) {
+			t.Errorf("%s: Vertices = %v, want %v", test.name, got, test.vertices)
+		}
+		for _, v := range test.vertices {
+			if !g.HasVertex(v) {
+				t.Errorf("%s: HasVertex(%s) = false", test.name, v.ID)
+			}
+		}
+		if g.HasVertex(Vertex{ID: "missing"}) {
+			t.Errorf("%s: HasVertex(missing) = true", test.name)
+		}
+	}
+}
+
+// TestDirectedGraphAddEdgeMany checks the indexes stay consistent
+// with the edges when adding many edges, each of them twice.
+func TestDirectedGraphAddEdgeMany(t *testing.T) {
+	edges := testEdges(10000)
+	g := &DirectedGraph{}
+	for _, e := range edges {
+		g.AddEdge(e)
+		g.AddEdge(e)
+	}
+
+	if got := g.EdgeCount(); got != len(edges) {
+		t.Errorf("EdgeCount = %d, want %d", got, len(edges))
+	}
+	out, in := 0, 0
+	for i, v := range g.Vertices() {
+		if g.index[v] != i {
+			t.Fatalf("index of %s is %d, want %d", v.ID, g.index[v], i)
+		}
+		out += len(g.OutEdges(v))
+		in += len(g.InEdges(v))
+	}
+	if out != len(edges) || in != len(edges) {
+		t.Errorf("vertices have %d out and %d in edges, want %d", out, in, len(edges))
+	}
+}
+
+// BenchmarkDirectedGraphAddEdge adds edges to an empty graph, and to
+// one grown to fit them.
+func BenchmarkDirectedGraphAddEdge(b *testing.B) {
+	for _, n := range []int{10000, 100000} {
+		edges := testEdges(n)
+		for _, grow := range []bool{false, true} {
+			name := strconv.Itoa(n)
+			if grow {
+				name += "/grown"
+			}
+			b.Run(name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					g := &DirectedGraph{}
+					if grow {
+						g.Grow(n/4+2, n)
+					}
+					for _, e := range edges {
+						g.AddEdge(e)
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkReadDirectedGraph reads CSV files of growing size, the
+// time per edge should stay about the same.
+func BenchmarkReadDirectedGraph(b *testing.B) {
+	for _, n := range []int{10000, 100000} {
+		csv := testCSV(testEdges(n))
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, _, err := ReadDirectedGraph(bytes.NewReader(csv), LoadOptions{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkLoadDirectedGraph(b *testing.B) {
+	paths, err := filepath.Glob(filepath.Join("csv_files", "*.csv"))
+	if err != nil || len(paths) == 0 {
+		b.Fatalf("no csv_files found: %v", err)
+	}
+
+	for _, path := range paths {
+		b.Run(filepath.Base(path), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, _, err := LoadDirectedGraph(path, LoadOptions{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestDirectedGraphRemove(t *testing.T) {
+	a, b, c := Vertex{ID: "a"}, Vertex{ID: "b"}, Vertex{ID: "c"}
+	ab := Edge{Start: a, End: b, ID: "ab"}
+	bc := Edge{Start: b, End: c, ID: "bc"}
+	ca := Edge{Start: c, End: a, ID: "ca"}
+	abParallel := Edge{Start: a, End: b, Weight: 1, ID: "ab"}
+
+	tests := []struct {
+		name     string
+		remove   func(g *DirectedGraph) bool
+		removed  bool
+		vertices string
+		count    int
+	}{
+		{"edge", func(g *DirectedGraph) bool { return g.RemoveEdge(bc) }, true, "[{a} {b} {c}]", 3},
+		{"reversed edge", func(g *DirectedGraph) bool { return g.RemoveEdge(bc.Reverse()) }, false, "[{a} {b} {c}]", 4},
+		{"missing edge", func(g *DirectedGraph) bool { return g.RemoveEdge(Edge{Start: a, End: c}) }, false, "[{a} {b} {c}]", 4},
+		{"edges by ID", func(g *DirectedGraph) bool { return g.RemoveEdgeByID("ab") }, true, "[{a} {b} {c}]", 2},
+		{"missing ID", func(g *DirectedGraph) bool { return g.RemoveEdgeByID("xx") }, false, "[{a} {b} {c}]", 4},
+		{"vertex", func(g *DirectedGraph) bool { return g.RemoveVertex(b) }, true, "[{a} {c}]", 1},
+		{"missing vertex", func(g *DirectedGraph) bool { return g.RemoveVertex(Vertex{ID: "x"}) }, false, "[{a} {b} {c}]", 4},
+	}
+
+	for _, test := range tests {
+		g := &DirectedGraph{}
+		for _, e := range []Edge{ab, bc, ca, abParallel} {
+			g.AddEdge(e)
+		}
+		g.SetEdgeAttribute(bc, "label", "x")
+		before := g.Vertices()
+
+		if removed := test.remove(g); removed != test.removed {
+			t.Errorf("%s: removing returned %t, want %t", test.name, removed, test.removed)
+		}
+		if got := fmt.Sprint(before); got != "[{a} {b} {c}]" {
+			t.Errorf("%s: Vertices returned before removing changed to %s", test.name, got)
+		}
+		if got := fmt.Sprint(g.Vertices()); got != test.vertices {
+			t.Errorf("%s: Vertices = %s, want %s", test.name, got, test.vertices)
+		}
+		checkEdges(t, test.name, g, test.count)
+		for i, v := range g.Vertices() {
+			if g.index[v] != i {
+				t.Errorf("%s: index of %s is %d, want %d", test.name, v.ID, g.index[v], i)
+			}
+		}
+		if _, ok := g.EdgeByID("bc"); !ok && g.EdgeAttributes(bc) != nil {
+			t.Errorf("%s: the attributes of a removed edge are kept", test.name)
+		}
+	}
+}
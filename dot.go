@@ -0,0 +1,717 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// WriteDOT writes g to w in the Graphviz DOT language, as a digraph if
+// g is directed. Every vertex is listed, followed by the edges. Edges
+// carry their ID and weight, their capacity and cost if set, and their
+// attributes as DOT attributes, and vertices their attributes.
+func WriteDOT(w io.Writer, g Graph) error {
+	return WriteHighlightedDOT(w, g, Highlight{})
+}
+
+// WriteDOTOf writes g to w like WriteDOT, for graphs of any weight
+// type.
+func WriteDOTOf[W Number](w io.Writer, g GraphOf[string, W]) error {
+	return WriteHighlightedDOTOf(w, g, HighlightOf[W]{})
+}
+
+// HighlightOf holds the results of an algorithm to draw on top of
+// a graph with WriteHighlightedDOTOf. Edges of undirected graphs
+// may be given in either direction.
+type HighlightOf[W Number] struct {
+	Edges        []EdgeOf[string, W]       // Drawn bold, e.g. a spanning tree or a matching
+	Path         []EdgeOf[string, W]       // Drawn bold and red along with its vertices, e.g. a shortest path
+	VertexColors map[Vertex]int            // Vertices are filled with the palette color of their number
+	EdgeColors   map[EdgeOf[string, W]]int // Edges are drawn in the palette color of their number
+	Flow         map[EdgeOf[string, W]]W   // Edges are labeled "flow/capacity", and drawn bold if used
+}
+
+// Highlight holds the results of an algorithm to draw on top of
+// a graph with WriteHighlightedDOT.
+type Highlight = HighlightOf[int64]
+
+// dotPalette holds the colors used for the numbered colors of a
+// Highlight, which wrap around after the last one. They are those
+// of the Graphviz set312 color scheme, easy to tell apart.
+var dotPalette = []string{
+	"#8dd3c7", "#ffffb3", "#bebada", "#fb8072", "#80b1d3", "#fdb462",
+	"#b3de69", "#fccde5", "#d9d9d9", "#bc80bd", "#ccebc5", "#ffed6f",
+}
+
+// paletteColor returns the palette color numbered color.
+func paletteColor(color int) string {
+	color %= len(dotPalette)
+	if color < 0 {
+		color += len(dotPalette)
+	}
+	return dotPalette[color]
+}
+
+// WriteHighlightedDOT writes g to w like WriteDOT, with the results in
+// h drawn on top through the style, color and label of the vertices
+// and edges. Those override any attributes by the same names.
+func WriteHighlightedDOT(w io.Writer, g Graph, h Highlight) error {
+	return WriteHighlightedDOTOf(w, g, h)
+}
+
+// WriteHighlightedDOTOf writes g to w like WriteHighlightedDOT, for
+// graphs of any weight type.
+func WriteHighlightedDOTOf[W Number](w io.Writer, g GraphOf[string, W], h HighlightOf[W]) error {
+	b := bufio.NewWriter(w)
+
+	directed := isDirected(g)
+	kind, connector := "graph", "--"
+	if directed {
+		kind, connector = "digraph", "->"
+	}
+
+	bold, onPath, pathVertices := make(map[EdgeOf[string, W]]bool), make(map[EdgeOf[string, W]]bool), make(map[Vertex]bool)
+	for _, e := range h.Edges {
+		bold[e] = true
+	}
+	for _, e := range h.Path {
+		onPath[e] = true
+		pathVertices[e.Start], pathVertices[e.End] = true, true
+	}
+
+	fmt.Fprintf(b, "%s {\n", kind)
+
+	for _, v := range g.Vertices() {
+		var fields [][2]string
+		color, colored := h.VertexColors[v]
+		if colored {
+			fields = append(fields, [2]string{"style", "filled"}, [2]string{"fillcolor", paletteColor(color)})
+		}
+		if pathVertices[v] {
+			fields = append(fields, [2]string{"color", "red"}, [2]string{"penwidth", "3"})
+		}
+		fmt.Fprintf(b, "\t%s%s;\n", dotID(v.ID), dotAttributes(g.VertexAttributes(v), fields))
+	}
+	for _, e := range g.Edges() {
+		fields := [][2]string{{"id", e.ID}, {WeightAttribute, formatNumber(e.Weight)}}
+		if e.Capacity != 0 {
+			fields = append(fields, [2]string{CapacityAttribute, formatNumber(e.Capacity)})
+		}
+		if e.Cost != 0 {
+			fields = append(fields, [2]string{CostAttribute, formatNumber(e.Cost)})
+		}
+
+		flow, hasFlow := lookupEdge(h.Flow, e, directed)
+		color, hasColor := lookupEdge(h.EdgeColors, e, directed)
+		isOnPath, _ := lookupEdge(onPath, e, directed)
+		isBold, _ := lookupEdge(bold, e, directed)
+
+		if isOnPath || isBold || hasFlow && flow != 0 {
+			fields = append(fields, [2]string{"style", "bold"}, [2]string{"penwidth", "3"})
+		}
+		if isOnPath {
+			fields = append(fields, [2]string{"color", "red"})
+		} else if hasColor {
+			fields = append(fields, [2]string{"color", paletteColor(color)})
+		}
+		if hasFlow {
+			fields = append(fields, [2]string{"label", formatNumber(flow) + "/" + formatNumber(e.Capacity)})
+		}
+
+		fmt.Fprintf(b, "\t%s %s %s%s;\n", dotID(e.Start.ID), connector, dotID(e.End.ID), dotAttributes(g.EdgeAttributes(e), fields))
+	}
+
+	fmt.Fprintf(b, "}\n")
+	return b.Flush()
+}
+
+// lookupEdge returns the value of e in m, also looking for it
+// in the other direction unless the graph is directed.
+func lookupEdge[K comparable, W Number, V any](m map[EdgeOf[K, W]]V, e EdgeOf[K, W], directed bool) (V, bool) {
+	value, ok := m[e]
+	if !ok && !directed {
+		value, ok = m[e.Reverse()]
+	}
+	return value, ok
+}
+
+// dotID quotes s as a DOT identifier. Only quotes are escaped, as
+// DOT keeps other backslashes and line breaks in strings as they are,
+// leaving escapes like \n in labels to Graphviz.
+func dotID(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// dotAttributes formats the given fields followed by the attributes,
+// in sorted order, as a DOT attribute list. Attributes named like
+// one of the fields are left out. It returns "" if there are none.
+func dotAttributes(attributes Attributes, fields [][2]string) string {
+	var list []string
+	taken := make(map[string]bool)
+	for _, field := range fields {
+		list = append(list, dotID(field[0])+"="+dotID(field[1]))
+		taken[field[0]] = true
+	}
+	for _, name := range sortedAttributeNames(attributes) {
+		if !taken[name] {
+			list = append(list, dotID(name)+"="+dotID(FormatAttribute(attributes[name])))
+		}
+	}
+
+	if len(list) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(list, ", ") + "]"
+}
+
+// dotToken is a token of the DOT language. Identifiers, numerals and
+// strings all have the kind dotID, with quotes and escapes removed.
+type dotToken struct {
+	kind  int
+	text  string
+	quote bool // Whether text was quoted, keywords must not be
+	line  int
+}
+
+// The kinds of DOT tokens, besides the single character punctuation
+// {}[];,=: which stand for themselves.
+const (
+	dotEOF = -1 - iota
+	dotIdentifier
+	dotEdgeOp
+)
+
+// dotLexer splits DOT input into tokens.
+type dotLexer struct {
+	r    *bufio.Reader
+	line int
+}
+
+func (l *dotLexer) errorf(format string, a ...any) *ParseError {
+	return &ParseError{Line: l.line, Reason: fmt.Sprintf(format, a...)}
+}
+
+func (l *dotLexer) read() (rune, error) {
+	c, _, err := l.r.ReadRune()
+	if c == '\n' {
+		l.line++
+	}
+	return c, err
+}
+
+func (l *dotLexer) unread(c rune) {
+	l.r.UnreadRune()
+	if c == '\n' {
+		l.line--
+	}
+}
+
+// skipLine skips the rest of the current line.
+func (l *dotLexer) skipLine() error {
+	for {
+		c, err := l.read()
+		if err == io.EOF || c == '\n' {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// next returns the next token, concatenating quoted strings joined by +.
+func (l *dotLexer) next() (dotToken, error) {
+	token, err := l.scan()
+	if err != nil || !token.quote {
+		return token, err
+	}
+	for {
+		plus, err := l.peekPlus()
+		if err != nil || !plus {
+			return token, err
+		}
+		more, err := l.scan()
+		if err != nil {
+			return token, err
+		}
+		if !more.quote {
+			return token, l.errorf("expected a string after +")
+		}
+		token.text += more.text
+	}
+}
+
+// peekPlus skips whitespace and consumes a + if that comes next.
+func (l *dotLexer) peekPlus() (bool, error) {
+	for {
+		c, err := l.read()
+		if err == io.EOF {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		if c == '+' {
+			return true, nil
+		}
+		if !unicode.IsSpace(c) {
+			l.unread(c)
+			return false, nil
+		}
+	}
+}
+
+// scan returns the next token.
+func (l *dotLexer) scan() (dotToken, error) {
+	for {
+		c, err := l.read()
+		if err == io.EOF {
+			return dotToken{kind: dotEOF, line: l.line}, nil
+		} else if err != nil {
+			return dotToken{}, err
+		}
+		line := l.line
+
+		switch {
+		case unicode.IsSpace(c):
+		case c == '#':
+			// Preprocessor output, ignored
+			if err := l.skipLine(); err != nil {
+				return dotToken{}, err
+			}
+		case c == '/':
+			next, err := l.read()
+			if err != nil && err != io.EOF {
+				return dotToken{}, err
+			}
+			switch next {
+			case '/':
+				if err := l.skipLine(); err != nil {
+					return dotToken{}, err
+				}
+			case '*':
+				for previous := rune(0); previous != '*' || next != '/'; {
+					previous = next
+					if next, err = l.read(); err == io.EOF {
+						return dotToken{}, l.errorf("unterminated comment")
+					} else if err != nil {
+						return dotToken{}, err
+					}
+				}
+			default:
+				return dotToken{}, l.errorf("unexpected /")
+			}
+		case strings.ContainsRune("{}[];,=:", c):
+			return dotToken{kind: int(c), line: line}, nil
+		case c == '-':
+			next, err := l.read()
+			if err != nil && err != io.EOF {
+				return dotToken{}, err
+			}
+			if next == '-' || next == '>' {
+				return dotToken{kind: dotEdgeOp, text: "-" + string(next), line: line}, nil
+			}
+			if err == nil {
+				l.unread(next)
+			}
+			return l.word(c, line)
+		case c == '"':
+			var text strings.Builder
+			for {
+				c, err := l.read()
+				if err == io.EOF {
+					return dotToken{}, l.errorf("unterminated string")
+				} else if err != nil {
+					return dotToken{}, err
+				}
+				if c == '"' {
+					break
+				}
+				if c == '\\' {
+					escaped, err := l.read()
+					if err != nil {
+						return dotToken{}, l.errorf("unterminated string")
+					}
+					switch escaped {
+					case '"':
+						c = '"'
+					case '\n':
+						continue // A line continuation
+					default:
+						text.WriteRune(c)
+						c = escaped
+					}
+				}
+				text.WriteRune(c)
+			}
+			return dotToken{kind: dotIdentifier, text: text.String(), quote: true, line: line}, nil
+		case c == '<':
+			// An HTML string, up to the matching >
+			var text strings.Builder
+			for depth := 1; ; {
+				c, err := l.read()
+				if err == io.EOF {
+					return dotToken{}, l.errorf("unterminated HTML string")
+				} else if err != nil {
+					return dotToken{}, err
+				}
+				if c == '<' {
+					depth++
+				} else if c == '>' {
+					if depth--; depth == 0 {
+						break
+					}
+				}
+				text.WriteRune(c)
+			}
+			return dotToken{kind: dotIdentifier, text: text.String(), quote: true, line: line}, nil
+		default:
+			return l.word(c, line)
+		}
+	}
+}
+
+// word scans an identifier or a numeral starting with c.
+func (l *dotLexer) word(c rune, line int) (dotToken, error) {
+	isWordRune := func(c rune) bool {
+		return c == '_' || c == '.' || unicode.IsLetter(c) || unicode.IsDigit(c) || c >= 0x80
+	}
+	if c != '-' && !isWordRune(c) {
+		return dotToken{}, l.errorf("unexpected %q", c)
+	}
+
+	var text strings.Builder
+	text.WriteRune(c)
+	for {
+		c, err := l.read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return dotToken{}, err
+		}
+		if !isWordRune(c) {
+			l.unread(c)
+			break
+		}
+		text.WriteRune(c)
+	}
+	return dotToken{kind: dotIdentifier, text: text.String(), line: line}, nil
+}
+
+// dotParser reads the statements of a DOT graph into a graphReader.
+type dotParser[W Number] struct {
+	lexer   *dotLexer
+	token   dotToken // The current token
+	gr      *graphReader[W]
+	defined map[Vertex]bool // Vertices which have been given their defaults
+}
+
+// dotScope holds the default node and edge attributes in a graph
+// or subgraph.
+type dotScope struct {
+	node, edge Attributes
+}
+
+func (s dotScope) copy() dotScope {
+	return dotScope{node: mergeAttributes(nil, s.node), edge: mergeAttributes(nil, s.edge)}
+}
+
+func (p *dotParser[W]) advance() error {
+	token, err := p.lexer.next()
+	p.token = token
+	return err
+}
+
+// keyword reports whether the current token is the given keyword,
+// which are case insensitive.
+func (p *dotParser[W]) keyword(word string) bool {
+	return p.token.kind == dotIdentifier && !p.token.quote && strings.EqualFold(p.token.text, word)
+}
+
+func (p *dotParser[W]) expect(kind int) error {
+	if p.token.kind != kind {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+func (p *dotParser[W]) unexpected() *ParseError {
+	switch p.token.kind {
+	case dotEOF:
+		return &ParseError{Line: p.token.line, Reason: "unexpected end of input"}
+	case dotIdentifier, dotEdgeOp:
+		return &ParseError{Line: p.token.line, Reason: fmt.Sprintf("unexpected %q", p.token.text)}
+	}
+	return &ParseError{Line: p.token.line, Reason: fmt.Sprintf("unexpected %q", rune(p.token.kind))}
+}
+
+// readDOT reads a graph in the DOT language. The id, weight, capacity
+// and cost attributes of edges are read into the Edge fields, while
+// any other node and edge attributes, including those set as defaults
+// with node [...] and edge [...], are kept as attributes. Subgraphs
+// are flattened into the graph, and edges without an id are numbered.
+func (gr *graphReader[W]) readDOT(r io.Reader) error {
+	p := &dotParser[W]{lexer: &dotLexer{r: bufio.NewReader(r), line: 1}, gr: gr, defined: make(map[Vertex]bool)}
+	if err := p.advance(); err != nil {
+		return err
+	}
+
+	if p.keyword("strict") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+	if !p.keyword("graph") && !p.keyword("digraph") {
+		return p.unexpected()
+	}
+	if err := p.advance(); err != nil {
+		return err
+	}
+	if p.token.kind == dotIdentifier {
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+
+	_, err := p.block(dotScope{node: Attributes{}, edge: Attributes{}})
+	return err
+}
+
+// block parses a { stmt_list } and returns the vertices in it.
+func (p *dotParser[W]) block(scope dotScope) ([]Vertex, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+
+	var vertices []Vertex
+	for p.token.kind != '}' {
+		stmtVertices, err := p.statement(&scope)
+		if err != nil {
+			return nil, err
+		}
+		vertices = append(vertices, stmtVertices...)
+		if p.token.kind == ';' || p.token.kind == ',' {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return vertices, p.advance()
+}
+
+// statement parses a single statement and returns the vertices in it.
+func (p *dotParser[W]) statement(scope *dotScope) ([]Vertex, error) {
+	// Attribute statements: graph, node or edge [...]
+	for _, kind := range []string{"graph", "node", "edge"} {
+		if !p.keyword(kind) {
+			continue
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		attributes, err := p.attributeList()
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case "node":
+			scope.node = mergeAttributes(scope.node, attributes)
+		case "edge":
+			scope.edge = mergeAttributes(scope.edge, attributes)
+		}
+		return nil, nil
+	}
+
+	line := p.token.line
+	endpoint, isID, err := p.endpoint(*scope)
+	if err != nil {
+		return nil, err
+	}
+
+	// A graph attribute: ID = ID
+	if isID && p.token.kind == '=' {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return nil, p.expect(dotIdentifier)
+	}
+
+	if p.token.kind != dotEdgeOp {
+		// A node statement, or a lone subgraph
+		attributes, err := p.attributeList()
+		if err != nil {
+			return nil, err
+		}
+		if isID {
+			p.defineVertex(endpoint[0], mergeAttributes(scope.node, attributes))
+		}
+		return endpoint, nil
+	}
+
+	// An edge statement: endpoint (-- | ->) endpoint ...
+	endpoints := [][]Vertex{endpoint}
+	for p.token.kind == dotEdgeOp {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		endpoint, _, err := p.endpoint(*scope)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	attributes, err := p.attributeList()
+	if err != nil {
+		return nil, err
+	}
+	attributes = mergeAttributes(scope.edge, attributes)
+
+	var vertices []Vertex
+	for i, from := range endpoints {
+		for _, v := range from {
+			p.defineVertex(v, scope.node)
+		}
+		vertices = append(vertices, from...)
+		if i == 0 {
+			continue
+		}
+		for _, start := range endpoints[i-1] {
+			for _, end := range from {
+				if err := p.addEdge(start, end, attributes, line); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return vertices, nil
+}
+
+// endpoint parses a node ID (with an optional port, which is ignored)
+// or a subgraph, and returns its vertices. It reports whether it was
+// a node ID.
+func (p *dotParser[W]) endpoint(scope dotScope) ([]Vertex, bool, error) {
+	if p.keyword("subgraph") || p.token.kind == '{' {
+		if p.keyword("subgraph") {
+			if err := p.advance(); err != nil {
+				return nil, false, err
+			}
+			if p.token.kind == dotIdentifier {
+				if err := p.advance(); err != nil {
+					return nil, false, err
+				}
+			}
+		}
+		vertices, err := p.block(scope.copy())
+		return vertices, false, err
+	}
+
+	if p.token.kind != dotIdentifier {
+		return nil, false, p.unexpected()
+	}
+	v := Vertex{ID: p.token.text}
+	if err := p.advance(); err != nil {
+		return nil, false, err
+	}
+	// A port and compass point, e.g. a:p:n
+	for p.token.kind == ':' {
+		if err := p.advance(); err != nil {
+			return nil, false, err
+		}
+		if err := p.expect(dotIdentifier); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return []Vertex{v}, true, nil
+}
+
+// attributeList parses any number of [a=b, c=d] lists. The values
+// are kept as the strings they were written as, see typedAttributes.
+func (p *dotParser[W]) attributeList() (Attributes, error) {
+	attributes := make(Attributes)
+	for p.token.kind == '[' {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		for p.token.kind != ']' {
+			if p.token.kind != dotIdentifier {
+				return nil, p.unexpected()
+			}
+			name := p.token.text
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			value := "true" // A name on its own
+			if p.token.kind == '=' {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+				if p.token.kind != dotIdentifier {
+					return nil, p.unexpected()
+				}
+				value = p.token.text
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			}
+			attributes[name] = value
+
+			if p.token.kind == ',' || p.token.kind == ';' {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	return attributes, nil
+}
+
+// typedAttributes returns the attributes read by attributeList
+// converted into the types ParseAttribute gives them.
+func typedAttributes(attributes Attributes) Attributes {
+	typed := make(Attributes, len(attributes))
+	for name, value := range attributes {
+		typed[name] = ParseAttribute(value.(string))
+	}
+	return typed
+}
+
+// defineVertex adds v to the graph with the given attributes. The
+// defaults of the scope only apply where a vertex first appears.
+func (p *dotParser[W]) defineVertex(v Vertex, attributes Attributes) {
+	if !p.defined[v] {
+		p.defined[v] = true
+		p.gr.g.AddVertex(v)
+	} else if len(attributes) == 0 {
+		return
+	}
+	p.gr.setVertexAttributes(v, typedAttributes(attributes))
+}
+
+// addEdge adds an edge from start to end with the given attributes.
+// The id is used as written, while the other attributes are typed.
+func (p *dotParser[W]) addEdge(start, end Vertex, attributes Attributes, line int) error {
+	id, hasID := attributes[IDField]
+	attributes = typedAttributes(attributes)
+	delete(attributes, IDField)
+
+	e := EdgeOf[string, W]{Start: start, End: end, Weight: -1}
+	if err := takeEdgeFields(&e, attributes); err != nil {
+		return p.gr.skip(&ParseError{Line: line, Reason: fmt.Sprintf("edge %s -> %s: %s", start.ID, end.ID, err)})
+	}
+	if hasID {
+		e.ID = id.(string)
+	} else {
+		e.ID = p.gr.nextID()
+	}
+
+	p.gr.g.AddEdge(e)
+	p.gr.setEdgeAttributes(e, attributes)
+	return nil
+}
@@ -0,0 +1,224 @@
+package graph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	directed, undirected := &DirectedGraph{}, &UndirectedGraph{}
+	buildAttributeGraph(directed)
+	buildAttributeGraph(undirected)
+
+	tests := []struct {
+		g    Graph
+		want string
+	}{
+		{directed, `digraph {
+	"a" ["x"="1.5", "zip"="01234"];
+	"b b";
+	"c" ["seen"="true", "x"="2.0"];
+	"isolated";
+	"a" -> "b b" ["id"="007", "weight"="2", "capacity"="3", "label"="x \"y\", z", "rank"="1"];
+	"b b" -> "c" ["id"="e2", "weight"="-1", "cost"="4", "label"="true"];
+}
+`},
+		{undirected, `graph {
+	"a" ["x"="1.5", "zip"="01234"];
+	"b b";
+	"c" ["seen"="true", "x"="2.0"];
+	"isolated";
+	"a" -- "b b" ["id"="007", "weight"="2", "capacity"="3", "label"="x \"y\", z", "rank"="1"];
+	"b b" -- "c" ["id"="e2", "weight"="-1", "cost"="4", "label"="true"];
+}
+`},
+	}
+
+	for _, test := range tests {
+		var b strings.Builder
+		if err := WriteDOT(&b, test.g); err != nil {
+			t.Fatalf("WriteDOT failed: %s", err)
+		}
+		if b.String() != test.want {
+			t.Errorf("WriteDOT wrote\n%s\nwant\n%s", b.String(), test.want)
+		}
+	}
+}
+
+func TestReadDOT(t *testing.T) {
+	input := `/* A test graph */
+strict digraph "G" {
+	graph [rankdir=LR];
+	label = "ignored";
+	node [shape=box];
+	a [label="007"];
+	edge [color=red];
+	a -> b [id=007, weight=2, capacity=3];
+	// A chain and a subgraph
+	b -> c -> {d; e} [weight=1];
+	subgraph cluster_x {
+		node [shape=circle];
+		f:port:n -> "g h" [color=blue, dashed];
+	}
+	# A preprocessor-style comment
+	d [shape=point]
+}`
+
+	g, warnings := readTest(t, input, LoadOptions{Format: FormatDOT, Strict: true})
+	if len(warnings) > 0 {
+		t.Errorf("warnings = %v", warnings)
+	}
+	if got, want := edgeSummary(g), "a>b:2:007 b>c:1:e1 c>d:1:e2 c>e:1:e3 f>g h:-1:e4"; got != want {
+		t.Errorf("read edges %s, want %s", got, want)
+	}
+
+	ab, _ := g.EdgeByID("007")
+	fg, _ := g.EdgeByID("e4")
+	tests := []struct {
+		attributes Attributes
+		name       string
+		want       any
+	}{
+		{g.VertexAttributes(Vertex{ID: "a"}), "label", int64(7)},
+		{g.VertexAttributes(Vertex{ID: "a"}), "shape", "box"},
+		{g.VertexAttributes(Vertex{ID: "d"}), "shape", "point"},
+		{g.VertexAttributes(Vertex{ID: "f"}), "shape", "circle"},
+		{g.EdgeAttributes(ab), "color", "red"},
+		{g.EdgeAttributes(fg), "color", "blue"},
+		{g.EdgeAttributes(fg), "dashed", true},
+	}
+	for _, test := range tests {
+		if got := test.attributes[test.name]; got != test.want {
+			t.Errorf("%s = %#v, want %#v", test.name, got, test.want)
+		}
+	}
+	if ab.Capacity != 3 {
+		t.Errorf("capacity of 007 = %d, want 3", ab.Capacity)
+	}
+}
+
+func TestReadDOTErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		line  int
+	}{
+		{"not a graph", "tree { a }", 1},
+		{"unclosed", "graph {\n a -- b", 2},
+		{"bad weight", "graph {\n a -- b [weight=x]\n}", 2},
+		{"bad attribute", "graph {\n a [=b]\n}", 2},
+	}
+
+	for _, test := range tests {
+		_, _, err := ReadDirectedGraph(strings.NewReader(test.input), LoadOptions{Format: FormatDOT, Strict: true})
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || parseErr.Line != test.line {
+			t.Errorf("%s: ReadDirectedGraph returned %v, want a *ParseError on line %d", test.name, err, test.line)
+		}
+	}
+}
+
+func TestDOTRoundTrip(t *testing.T) {
+	g := &UndirectedGraph{}
+	buildAttributeGraph(g)
+	// DOT values are untyped, so a string like "01234" reads back as a number
+	g.SetVertexAttribute(Vertex{ID: "a"}, "zip", "FI-01234")
+	// Backslashes and line breaks are kept as they are
+	windows, lines := Vertex{ID: `C:\graphs`}, Vertex{ID: "two\nlines"}
+	g.AddEdge(Edge{ID: `e\3`, Start: windows, End: lines, Weight: 1})
+	g.SetVertexAttribute(windows, "label", `line\nbreak`)
+
+	var b strings.Builder
+	if err := WriteDOT(&b, g); err != nil {
+		t.Fatal(err)
+	}
+	read, _, err := ReadUndirectedGraph(strings.NewReader(b.String()), LoadOptions{Format: FormatDOT, Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := describeGraph(read), describeGraph(g); got != want {
+		t.Errorf("read back\n%s\nas\n%s\nwant\n%s", b.String(), got, want)
+	}
+}
+
+func TestWriteHighlightedDOT(t *testing.T) {
+	a, b, c := Vertex{ID: "a"}, Vertex{ID: "b"}, Vertex{ID: "c"}
+	ab := Edge{Start: a, End: b, Weight: 1, Capacity: 2, ID: "ab"}
+	bc := Edge{Start: b, End: c, Weight: 1, Capacity: 2, ID: "bc"}
+	ac := Edge{Start: a, End: c, Weight: 3, Capacity: 1, ID: "ac"}
+
+	tests := []struct {
+		name  string
+		g     Graph
+		edges []Edge // Edges as given to the Highlight
+		want  string
+	}{
+		{"directed", &DirectedGraph{}, []Edge{ab, bc, ac}, `digraph {
+	"a" ["style"="filled", "fillcolor"="#8dd3c7", "color"="red", "penwidth"="3"];
+	"b" ["color"="red", "penwidth"="3"];
+	"c" ["style"="filled", "fillcolor"="#ffffb3"];
+	"a" -> "b" ["id"="ab", "weight"="1", "capacity"="2", "style"="bold", "penwidth"="3", "color"="red", "label"="1/2"];
+	"a" -> "c" ["id"="ac", "weight"="3", "capacity"="1", "style"="bold", "penwidth"="3", "label"="0/1"];
+	"b" -> "c" ["id"="bc", "weight"="1", "capacity"="2", "style"="bold", "penwidth"="3", "color"="#ffed6f", "label"="1/2"];
+}
+`},
+		// Undirected graphs find the highlighted edges in either direction
+		{"undirected", &UndirectedGraph{}, []Edge{ab.Reverse(), bc.Reverse(), ac.Reverse()}, `graph {
+	"a" ["style"="filled", "fillcolor"="#8dd3c7", "color"="red", "penwidth"="3"];
+	"b" ["color"="red", "penwidth"="3"];
+	"c" ["style"="filled", "fillcolor"="#ffffb3"];
+	"a" -- "b" ["id"="ab", "weight"="1", "capacity"="2", "style"="bold", "penwidth"="3", "color"="red", "label"="1/2"];
+	"a" -- "c" ["id"="ac", "weight"="3", "capacity"="1", "style"="bold", "penwidth"="3", "label"="0/1"];
+	"b" -- "c" ["id"="bc", "weight"="1", "capacity"="2", "style"="bold", "penwidth"="3", "color"="#ffed6f", "label"="1/2"];
+}
+`},
+	}
+
+	for _, test := range tests {
+		builder := test.g.(graphBuilder[int64])
+		for _, e := range []Edge{ab, ac, bc} {
+			builder.AddEdge(e)
+		}
+		// Highlighting overrides attributes by the same names
+		builder.SetEdgeAttribute(bc, "color", "green")
+
+		hab, hbc, hac := test.edges[0], test.edges[1], test.edges[2]
+		h := Highlight{
+			Edges:        []Edge{hac},
+			Path:         []Edge{hab},
+			VertexColors: map[Vertex]int{a: 0, c: 13},
+			EdgeColors:   map[Edge]int{hab: 1, hbc: -1},
+			Flow:         map[Edge]int64{hab: 1, hbc: 1, hac: 0},
+		}
+
+		var out strings.Builder
+		if err := WriteHighlightedDOT(&out, test.g, h); err != nil {
+			t.Fatalf("%s: WriteHighlightedDOT failed: %s", test.name, err)
+		}
+		if out.String() != test.want {
+			t.Errorf("%s: WriteHighlightedDOT wrote\n%s\nwant\n%s", test.name, out.String(), test.want)
+		}
+	}
+}
+
+func TestPaletteColor(t *testing.T) {
+	n := len(dotPalette)
+	tests := []struct {
+		color int
+		want  string
+	}{
+		{0, dotPalette[0]},
+		{n - 1, dotPalette[n-1]},
+		{n, dotPalette[0]},
+		{2*n + 3, dotPalette[3]},
+		{-1, dotPalette[n-1]},
+		{-n, dotPalette[0]},
+	}
+
+	for _, test := range tests {
+		if got := paletteColor(test.color); got != test.want {
+			t.Errorf("paletteColor(%d) = %s, want %s", test.color, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// readEdgeList reads a whitespace separated edge list, as used by the
+// SNAP datasets: one edge per line given by its source, its target and
+// optionally its weight. Empty lines and lines starting with # or %
+// are skipped. The edges are numbered in the order they are read.
+func (gr *graphReader[W]) readEdgeList(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "%") {
+			continue
+		}
+
+		values := strings.Fields(text)
+		if len(values) != 2 && len(values) != 3 {
+			if err := gr.skip(&ParseError{Line: line, Reason: fmt.Sprintf("expected 2 or 3 values, got %d", len(values))}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		e := EdgeOf[string, W]{Start: Vertex{ID: values[0]}, End: Vertex{ID: values[1]}, Weight: -1}
+		if len(values) == 3 {
+			weight, err := parseNumber[W](values[2])
+			if err != nil {
+				if err := gr.skip(&ParseError{Line: line, Column: 3, Reason: "invalid weight: " + err.Error()}); err != nil {
+					return err
+				}
+				continue
+			}
+			e.Weight = weight
+		}
+
+		e.ID = gr.nextID()
+		gr.g.AddEdge(e)
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,31 @@
+package graph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadEdgeList(t *testing.T) {
+	input := "# Directed graph\n% also a comment\n\n1 2\n2\t3 7\n  3 1 -4  \n1 2 3 4\n2 1 x\n"
+	g, warnings := readTest(t, input, LoadOptions{Format: FormatEdgeList})
+
+	if got, want := edgeSummary(g), "1>2:-1:e1 2>3:7:e2 3>1:-4:e3"; got != want {
+		t.Errorf("read edges %s, want %s", got, want)
+	}
+	if len(warnings) != 2 {
+		t.Fatalf("got warnings %v, want 2", warnings)
+	}
+	if w := warnings[0]; w.Line != 7 || w.Reason != "expected 2 or 3 values, got 4" {
+		t.Errorf("warning for 4 values is %v", w)
+	}
+	if w := warnings[1]; w.Line != 8 || w.Column != 3 || !strings.HasPrefix(w.Reason, "invalid weight") {
+		t.Errorf("warning for an invalid weight is %v", w)
+	}
+
+	_, _, err := ReadDirectedGraph(strings.NewReader(input), LoadOptions{Format: FormatEdgeList, Strict: true})
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 7 {
+		t.Errorf("strict reading returned %v, want an error on line 7", err)
+	}
+}
@@ -0,0 +1,235 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// gmlPair is a key and its value in a GML list. The value is an
+// int64, a float64, a string or a []gmlPair for a nested list, and
+// raw the text it was read from, without quotes.
+type gmlPair struct {
+	key   string
+	value any
+	raw   string
+	line  int
+}
+
+// gmlParser parses GML from a stream of bytes.
+type gmlParser struct {
+	r    *bufio.Reader
+	line int
+}
+
+// errorf returns a *ParseError for the current line.
+func (p *gmlParser) errorf(format string, a ...any) *ParseError {
+	return &ParseError{Line: p.line, Reason: fmt.Sprintf(format, a...)}
+}
+
+// token returns the next token: a bracket, a quoted string (with its
+// quotes) or a word. It returns "" at the end of the input.
+func (p *gmlParser) token() (string, error) {
+	for {
+		c, _, err := p.r.ReadRune()
+		if err == io.EOF {
+			return "", nil
+		} else if err != nil {
+			return "", err
+		}
+
+		switch {
+		case c == '\n':
+			p.line++
+		case unicode.IsSpace(c):
+		case c == '#':
+			// A comment, skip the rest of the line
+			if _, err := p.r.ReadString('\n'); err != nil && err != io.EOF {
+				return "", err
+			}
+			p.line++
+		case c == '[' || c == ']':
+			return string(c), nil
+		case c == '"':
+			s, err := p.r.ReadString('"')
+			if err == io.EOF {
+				return "", p.errorf("unterminated string")
+			} else if err != nil {
+				return "", err
+			}
+			p.line += strings.Count(s, "\n")
+			return `"` + s, nil
+		default:
+			var word strings.Builder
+			word.WriteRune(c)
+			for {
+				c, _, err := p.r.ReadRune()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					return "", err
+				}
+				if unicode.IsSpace(c) || c == '[' || c == ']' || c == '"' {
+					p.r.UnreadRune()
+					break
+				}
+				word.WriteRune(c)
+			}
+			return word.String(), nil
+		}
+	}
+}
+
+// list parses key value pairs up to the closing bracket of the
+// list, or to the end of the input at the top level.
+func (p *gmlParser) list(topLevel bool) ([]gmlPair, error) {
+	var pairs []gmlPair
+	for {
+		key, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "":
+			if !topLevel {
+				return nil, p.errorf("unterminated list")
+			}
+			return pairs, nil
+		case "]":
+			if topLevel {
+				return nil, p.errorf("unexpected ]")
+			}
+			return pairs, nil
+		case "[":
+			return nil, p.errorf("expected a key, got [")
+		}
+		if strings.HasPrefix(key, `"`) {
+			return nil, p.errorf("expected a key, got %s", key)
+		}
+
+		line := p.line
+		token, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		var value any
+		raw := strings.Trim(token, `"`)
+		switch {
+		case token == "":
+			return nil, p.errorf("missing value for %s", key)
+		case token == "[":
+			if value, err = p.list(false); err != nil {
+				return nil, err
+			}
+		case token == "]":
+			return nil, p.errorf("missing value for %s", key)
+		case strings.HasPrefix(token, `"`):
+			value = token[1 : len(token)-1]
+		default:
+			if i, err := strconv.ParseInt(token, 10, 64); err == nil {
+				value = i
+			} else if f, err := strconv.ParseFloat(token, 64); err == nil {
+				value = f
+			} else {
+				return nil, p.errorf("invalid value %q for %s", token, key)
+			}
+		}
+		pairs = append(pairs, gmlPair{key: key, value: value, raw: raw, line: line})
+	}
+}
+
+// readGML reads the first graph of a GML document. Nodes are identified
+// by their id, and their other values (such as their label) are kept
+// as attributes. Edges are read from their source, target and id, the
+// numeric fields from the values of the same name, and any other values
+// are kept as attributes. Nested lists, like graphics, are ignored.
+func (gr *graphReader[W]) readGML(r io.Reader) error {
+	p := &gmlParser{r: bufio.NewReader(r), line: 1}
+	document, err := p.list(true)
+	if err != nil {
+		return err
+	}
+
+	var graph []gmlPair
+	for _, pair := range document {
+		if list, ok := pair.value.([]gmlPair); ok && pair.key == "graph" {
+			graph = list
+			break
+		}
+	}
+	if graph == nil {
+		return &ParseError{Reason: "no graph found"}
+	}
+
+	// values collects the plain values of a node or an edge, and
+	// their text as written
+	values := func(list []gmlPair) (Attributes, map[string]string) {
+		attributes, raw := make(Attributes), make(map[string]string)
+		for _, pair := range list {
+			if _, nested := pair.value.([]gmlPair); !nested {
+				attributes[pair.key] = pair.value
+				raw[pair.key] = pair.raw
+			}
+		}
+		return attributes, raw
+	}
+	// take removes the named value, returning it as written for an ID
+	take := func(attributes Attributes, raw map[string]string, name string) (string, bool) {
+		_, ok := attributes[name]
+		delete(attributes, name)
+		return raw[name], ok
+	}
+
+	for _, pair := range graph {
+		list, ok := pair.value.([]gmlPair)
+		if !ok {
+			continue
+		}
+
+		switch pair.key {
+		case "node":
+			attributes, raw := values(list)
+			id, ok := take(attributes, raw, "id")
+			if !ok {
+				if err := gr.skip(&ParseError{Line: pair.line, Reason: "node without an id"}); err != nil {
+					return err
+				}
+				continue
+			}
+			v := Vertex{ID: id}
+			gr.g.AddVertex(v)
+			gr.setVertexAttributes(v, attributes)
+		case "edge":
+			attributes, raw := values(list)
+			source, hasSource := take(attributes, raw, "source")
+			target, hasTarget := take(attributes, raw, "target")
+			if !hasSource || !hasTarget {
+				if err := gr.skip(&ParseError{Line: pair.line, Reason: "edge without a source and a target"}); err != nil {
+					return err
+				}
+				continue
+			}
+
+			e := EdgeOf[string, W]{Start: Vertex{ID: source}, End: Vertex{ID: target}, Weight: -1}
+			if err := takeEdgeFields(&e, attributes); err != nil {
+				if err := gr.skip(&ParseError{Line: pair.line, Reason: fmt.Sprintf("edge %s -> %s: %s", source, target, err)}); err != nil {
+					return err
+				}
+				continue
+			}
+			if id, ok := take(attributes, raw, "id"); ok {
+				e.ID = id
+			} else {
+				e.ID = gr.nextID()
+			}
+
+			gr.g.AddEdge(e)
+			gr.setEdgeAttributes(e, attributes)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadGML(t *testing.T) {
+	input := `Creator "test"
+graph [
+  directed 1
+  # The id is kept as written
+  node [ id "007" label "first" graphics [ x 1.5 y 2 ] ]
+  node [ id 2 label "second" size 0.5 ]
+  node [ label "no id" ]
+  edge [ source "007" target 2 weight 3 capacity 4 id "e007" label "x y" ]
+  edge [ source 2 target 3 ]
+  edge [ source 2 ]
+]`
+	g, warnings := readTest(t, input, LoadOptions{Format: FormatGML})
+
+	if got, want := edgeSummary(g), "007>2:3:e007 2>3:-1:e1"; got != want {
+		t.Errorf("read edges %s, want %s", got, want)
+	}
+	if got := len(g.Vertices()); got != 3 {
+		t.Errorf("read %d vertices %v, want 3", got, g.Vertices())
+	}
+	if label, _ := g.VertexAttributes(Vertex{ID: "007"}).String("label"); label != "first" {
+		t.Errorf("label of 007 = %q, want first", label)
+	}
+	if _, ok := g.VertexAttributes(Vertex{ID: "007"})["graphics"]; ok {
+		t.Errorf("nested graphics list kept as an attribute")
+	}
+	if size, _ := g.VertexAttributes(Vertex{ID: "2"}).Float("size"); size != 0.5 {
+		t.Errorf("size of 2 = %g, want 0.5", size)
+	}
+	e, _ := g.EdgeByID("e007")
+	if e.Capacity != 4 {
+		t.Errorf("capacity of e007 = %d, want 4", e.Capacity)
+	}
+	if label, _ := g.EdgeAttributes(e).String("label"); label != "x y" {
+		t.Errorf("label of e007 = %q, want x y", label)
+	}
+
+	if len(warnings) != 2 {
+		t.Fatalf("got warnings %v, want 2", warnings)
+	}
+	if warnings[0].Line != 7 || warnings[0].Reason != "node without an id" {
+		t.Errorf("first warning is %v", warnings[0])
+	}
+	if warnings[1].Line != 10 || warnings[1].Reason != "edge without a source and a target" {
+		t.Errorf("second warning is %v", warnings[1])
+	}
+}
+
+func TestReadGMLErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		line   int
+		reason string
+	}{
+		{"no graph", `Creator "test"`, 0, "no graph found"},
+		{"unterminated list", "graph [\n  node [ id 1 ]\n", 3, "unterminated list"},
+		{"unterminated string", "graph [\n  label \"x\n]", 2, "unterminated string"},
+		{"unexpected bracket", "graph [ ] ]", 1, "unexpected ]"},
+		{"missing value", "graph [ node [ id ] ]", 1, "missing value for id"},
+		{"invalid value", "graph [ node [ id x ] ]", 1, `invalid value "x" for id`},
+		{"strict node", "graph [\n  node [ label 1 ]\n]", 2, "node without an id"},
+		{"strict edge", "graph [\n  edge [ source 1 target 2 weight 1.5 ]\n]", 2, "edge 1 -> 2: "},
+	}
+
+	for _, test := range tests {
+		_, _, err := ReadDirectedGraph(strings.NewReader(test.input), LoadOptions{Format: FormatGML, Strict: true})
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s: got %v, want a *ParseError", test.name, err)
+			continue
+		}
+		if parseErr.Line != test.line || !strings.HasPrefix(parseErr.Reason, test.reason) {
+			t.Errorf("%s: got error on line %d: %s, want line %d: %s", test.name, parseErr.Line, parseErr.Reason, test.line, test.reason)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/njern/graph
+
+go 1.21
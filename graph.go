@@ -1,38 +1,145 @@
-package main
+// Package graph contains implementations of various graph algorithms,
+// originally written for the 2014 edition of "Graph Algorithms" at
+// Åbo Akademi.
+package graph
 
-type Graph interface {
+// Number is the constraint for the weights, capacities and costs
+// of edges. Only signed types are allowed, as several algorithms rely
+// on negating them.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+// GraphOf is the common interface implemented by DirectedGraphOf and
+// UndirectedGraphOf. For an undirected graph every edge is an out edge
+// of both its endpoints, see UndirectedGraphOf.OutEdges.
+type GraphOf[K comparable, W Number] interface {
 	VertexCount() int
 	EdgeCount() int
 	String() string
+
+	// Vertices returns every vertex of the graph.
+	Vertices() []VertexOf[K]
+	// Edges returns every edge of the graph exactly once.
+	Edges() []EdgeOf[K, W]
+	// OutEdges returns the edges leaving v.
+	OutEdges(v VertexOf[K]) []EdgeOf[K, W]
+	// InEdges returns the edges entering v.
+	InEdges(v VertexOf[K]) []EdgeOf[K, W]
+	// Degree returns the number of edge endpoints at v,
+	// counting self loops twice.
+	Degree(v VertexOf[K]) int
+	// VertexAttributes returns the extra attributes of v.
+	VertexAttributes(v VertexOf[K]) Attributes
+	// EdgeAttributes returns the extra attributes of e.
+	EdgeAttributes(e EdgeOf[K, W]) Attributes
 }
 
-type Vertex struct {
-	id string
+// Graph is a graph with string vertex IDs and int64 weights,
+// the default instantiation of GraphOf.
+type Graph = GraphOf[string, int64]
+
+var (
+	_ Graph = (*DirectedGraph)(nil)
+	_ Graph = (*UndirectedGraph)(nil)
+)
+
+// isDirected reports whether g is a directed graph.
+func isDirected[K comparable, W Number](g GraphOf[K, W]) bool {
+	_, ok := g.(*DirectedGraphOf[K, W])
+	return ok
 }
 
-func (v *Vertex) Equals(v2 *Vertex) bool {
-	return v.id == v2.id
+// isMultigraph reports whether g is a multigraph, see
+// DirectedGraph.IsMultigraph.
+func isMultigraph[K comparable, W Number](g GraphOf[K, W]) bool {
+	m, ok := g.(interface{ IsMultigraph() bool })
+	return ok && m.IsMultigraph()
 }
 
-type Edge struct {
-	start  Vertex
-	end    Vertex
-	weight int64
-	id     string
+// VertexOf is a node in a graph, identified by its ID.
+type VertexOf[K comparable] struct {
+	ID K
 }
 
-func (e *Edge) Equals(e2 *Edge) bool {
-	return e.id == e2.id
+// Vertex is a vertex with a string ID.
+type Vertex = VertexOf[string]
+
+func (v *VertexOf[K]) Equals(v2 *VertexOf[K]) bool {
+	return v.ID == v2.ID
 }
 
-func (e *Edge) Reverse() Edge {
-	return Edge{e.end, e.start, e.weight, e.id}
+// EdgeOf connects a Start vertex to an End vertex. For undirected graphs
+// the direction only matters as far as which way the edge was read.
+// Path algorithms use the Weight of an edge, flow algorithms its
+// Capacity and minimum cost flow additionally its Cost.
+type EdgeOf[K comparable, W Number] struct {
+	Start    VertexOf[K]
+	End      VertexOf[K]
+	Weight   W
+	Capacity W
+	Cost     W
+	ID       string
 }
 
-type Vertices []Vertex
-type Edges []Edge
+// Edge is an edge between string vertices with int64 values.
+type Edge = EdgeOf[string, int64]
+
+// Names of the numeric attributes of an Edge, as used
+// by Attribute, SetAttribute and the CSV headers.
+const (
+	WeightAttribute   = "weight"
+	CapacityAttribute = "capacity"
+	CostAttribute     = "cost"
+)
+
+// Attribute returns the value of the named numeric attribute,
+// and false if there is no attribute by that name.
+func (e *EdgeOf[K, W]) Attribute(name string) (W, bool) {
+	switch name {
+	case WeightAttribute:
+		return e.Weight, true
+	case CapacityAttribute:
+		return e.Capacity, true
+	case CostAttribute:
+		return e.Cost, true
+	}
+	return 0, false
+}
+
+// SetAttribute sets the value of the named numeric attribute,
+// and reports false if there is no attribute by that name.
+func (e *EdgeOf[K, W]) SetAttribute(name string, value W) bool {
+	switch name {
+	case WeightAttribute:
+		e.Weight = value
+	case CapacityAttribute:
+		e.Capacity = value
+	case CostAttribute:
+		e.Cost = value
+	default:
+		return false
+	}
+	return true
+}
+
+func (e *EdgeOf[K, W]) Equals(e2 *EdgeOf[K, W]) bool {
+	return e.ID == e2.ID
+}
+
+func (e *EdgeOf[K, W]) Reverse() EdgeOf[K, W] {
+	reverse := *e
+	reverse.Start, reverse.End = e.End, e.Start
+	return reverse
+}
 
-func (v *Vertices) contains(vertex Vertex) bool {
+type VerticesOf[K comparable] []VertexOf[K]
+type EdgesOf[K comparable, W Number] []EdgeOf[K, W]
+
+type Vertices = VerticesOf[string]
+type Edges = EdgesOf[string, int64]
+
+func (v *VerticesOf[K]) contains(vertex VertexOf[K]) bool {
 	for _, existingVertex := range *v {
 		if existingVertex == vertex {
 			return true
@@ -41,7 +148,7 @@ func (v *Vertices) contains(vertex Vertex) bool {
 	return false
 }
 
-func (e *Edges) contains(edge Edge) bool {
+func (e *EdgesOf[K, W]) contains(edge EdgeOf[K, W]) bool {
 	for _, existingEdge := range *e {
 		if existingEdge == edge {
 			return true
@@ -50,61 +157,93 @@ func (e *Edges) contains(edge Edge) bool {
 	return false
 }
 
-// VertexStack is a FIFO stack that holds Vertex structs.
-type VertexStack struct {
-	top  *Element
+// removeEdge returns edges without the first occurrence of edge,
+// keeping the order of the remaining edges.
+func removeEdge[K comparable, W Number](edges []EdgeOf[K, W], edge EdgeOf[K, W]) []EdgeOf[K, W] {
+	for i, existingEdge := range edges {
+		if existingEdge == edge {
+			return append(edges[:i], edges[i+1:]...)
+		}
+	}
+	return edges
+}
+
+// growMap returns a copy of m with room for n more entries.
+func growMap[M ~map[K]V, K comparable, V any](m M, n int) M {
+	grown := make(M, len(m)+n)
+	for k, v := range m {
+		grown[k] = v
+	}
+	return grown
+}
+
+// VertexStackOf is a FIFO stack that holds vertices.
+type VertexStackOf[K comparable] struct {
+	top  *ElementOf[K]
 	size int
 }
 
-type Element struct {
-	value Vertex
-	next  *Element
+type ElementOf[K comparable] struct {
+	value VertexOf[K]
+	next  *ElementOf[K]
 }
 
+// VertexStack is a stack of vertices with string IDs.
+type VertexStack = VertexStackOf[string]
+type Element = ElementOf[string]
+
 // Return the stack's length
-func (s *VertexStack) Len() int {
+func (s *VertexStackOf[K]) Len() int {
 	return s.size
 }
 
 // Push a new element onto the stack
-func (s *VertexStack) Push(value Vertex) {
-	s.top = &Element{value, s.top}
+func (s *VertexStackOf[K]) Push(value VertexOf[K]) {
+	s.top = &ElementOf[K]{value, s.top}
 	s.size++
 }
 
 // Remove the top element from the stack and return it's value
 // If the stack is empty, return nil
-func (s *VertexStack) Pop() Vertex {
+func (s *VertexStackOf[K]) Pop() VertexOf[K] {
 	if s.size > 0 {
 		vertex := s.top.value
 		s.top = s.top.next
 		s.size--
 		return vertex
 	}
-	return Vertex{}
+	return VertexOf[K]{}
 }
 
-// Queue is a basic FIFO queue based on a circular list that resizes as needed.
-type Queue struct {
-	nodes []Vertex
+// QueueOf is a basic FIFO queue based on a circular list that resizes as needed.
+type QueueOf[K comparable] struct {
+	nodes []VertexOf[K]
 	size  int
 	head  int
 	tail  int
 	count int
 }
 
+// Queue is a queue of vertices with string IDs.
+type Queue = QueueOf[string]
+
 // NewQueue returns a new queue with the given initial size.
 func NewQueue(size int) *Queue {
-	return &Queue{
-		nodes: make([]Vertex, size),
+	return NewQueueOf[string](size)
+}
+
+// NewQueueOf returns a new queue with the given initial size.
+func NewQueueOf[K comparable](size int) *QueueOf[K] {
+	return &QueueOf[K]{
+		nodes: make([]VertexOf[K], size),
 		size:  size,
 	}
 }
 
 // Push adds a node to the queue.
-func (q *Queue) Push(n Vertex) {
+func (q *QueueOf[K]) Push(n VertexOf[K]) {
 	if q.head == q.tail && q.count > 0 {
-		nodes := make([]Vertex, len(q.nodes)+q.size)
+		nodes := make([]VertexOf[K], len(q.nodes)+q.size)
 		copy(nodes, q.nodes[q.head:])
 		copy(nodes[len(q.nodes)-q.head:], q.nodes[:q.head])
 		q.head = 0
@@ -117,9 +256,9 @@ func (q *Queue) Push(n Vertex) {
 }
 
 // Pop removes and returns a node from the queue in first to last order.
-func (q *Queue) Pop() Vertex {
+func (q *QueueOf[K]) Pop() VertexOf[K] {
 	if q.count == 0 {
-		return Vertex{}
+		return VertexOf[K]{}
 	}
 	node := q.nodes[q.head]
 	q.head = (q.head + 1) % len(q.nodes)
@@ -127,6 +266,122 @@ func (q *Queue) Pop() Vertex {
 	return node
 }
 
-func (q *Queue) Len() int {
+func (q *QueueOf[K]) Len() int {
 	return q.count
 }
+
+// VertexHeapOf is an indexed binary min-heap of vertices ordered by a
+// numeric priority. Keeping track of each vertex's position in the heap
+// allows its priority to be lowered in O(log n), which is what
+// Dijkstra's algorithm needs.
+type VertexHeapOf[K comparable, P Number] struct {
+	vertices   []VertexOf[K]
+	priorities []P
+	index      map[VertexOf[K]]int // [Vertex]Position in vertices
+}
+
+// VertexHeap is a heap of vertices with string IDs and int64 priorities.
+type VertexHeap = VertexHeapOf[string, int64]
+
+// NewVertexHeap returns an empty heap with room for size vertices.
+func NewVertexHeap(size int) *VertexHeap {
+	return NewVertexHeapOf[string, int64](size)
+}
+
+// NewVertexHeapOf returns an empty heap with room for size vertices.
+func NewVertexHeapOf[K comparable, P Number](size int) *VertexHeapOf[K, P] {
+	return &VertexHeapOf[K, P]{
+		vertices:   make([]VertexOf[K], 0, size),
+		priorities: make([]P, 0, size),
+		index:      make(map[VertexOf[K]]int, size),
+	}
+}
+
+// Len returns the number of vertices in the heap.
+func (h *VertexHeapOf[K, P]) Len() int {
+	return len(h.vertices)
+}
+
+// Contains reports whether v is currently in the heap.
+func (h *VertexHeapOf[K, P]) Contains(v VertexOf[K]) bool {
+	_, ok := h.index[v]
+	return ok
+}
+
+// Push adds v to the heap with the given priority. If v is
+// already in the heap its priority is updated instead.
+func (h *VertexHeapOf[K, P]) Push(v VertexOf[K], priority P) {
+	if i, ok := h.index[v]; ok {
+		old := h.priorities[i]
+		h.priorities[i] = priority
+		if priority < old {
+			h.up(i)
+		} else {
+			h.down(i)
+		}
+		return
+	}
+
+	h.vertices = append(h.vertices, v)
+	h.priorities = append(h.priorities, priority)
+	h.index[v] = len(h.vertices) - 1
+	h.up(len(h.vertices) - 1)
+}
+
+// Pop removes and returns the vertex with the lowest priority
+// together with its priority. If the heap is empty, return an
+// empty Vertex.
+func (h *VertexHeapOf[K, P]) Pop() (VertexOf[K], P) {
+	if len(h.vertices) == 0 {
+		return VertexOf[K]{}, 0
+	}
+
+	v, priority := h.vertices[0], h.priorities[0]
+	last := len(h.vertices) - 1
+	h.swap(0, last)
+	h.vertices = h.vertices[:last]
+	h.priorities = h.priorities[:last]
+	delete(h.index, v)
+	if last > 0 {
+		h.down(0)
+	}
+
+	return v, priority
+}
+
+func (h *VertexHeapOf[K, P]) swap(i, j int) {
+	h.vertices[i], h.vertices[j] = h.vertices[j], h.vertices[i]
+	h.priorities[i], h.priorities[j] = h.priorities[j], h.priorities[i]
+	h.index[h.vertices[i]] = i
+	h.index[h.vertices[j]] = j
+}
+
+func (h *VertexHeapOf[K, P]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if h.priorities[parent] <= h.priorities[i] {
+			break
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+func (h *VertexHeapOf[K, P]) down(i int) {
+	n := len(h.vertices)
+	for {
+		smallest := i
+		left, right := 2*i+1, 2*i+2
+		if left < n && h.priorities[left] < h.priorities[smallest] {
+			smallest = left
+		}
+		if right < n && h.priorities[right] < h.priorities[smallest] {
+			smallest = right
+		}
+		if smallest == i {
+			return
+		}
+		h.swap(i, smallest)
+		i = smallest
+	}
+}
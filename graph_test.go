@@ -0,0 +1,311 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestPrimMST checks the library against the known answer for
+// benchmark3.csv, the tree printed by graph -prim.
+func TestPrimMST(t *testing.T) {
+	answer, err := os.ReadFile(filepath.Join("csv_files", "answer_benchmark3.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := NewUndirectedGraphFromFile(filepath.Join("csv_files", "benchmark3.csv"), '\t')
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, e := range g.PrimMST(g.Vertices()[0]) {
+		ids = append(ids, e.ID)
+	}
+	sort.Strings(ids)
+	if got, want := strings.Join(ids, ","), strings.TrimSpace(string(answer)); got != want {
+		t.Errorf("PrimMST = %s, want %s", got, want)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	tests := []struct {
+		size  int
+		count int
+	}{
+		{1, 1},
+		{1, 5}, // Grows
+		{4, 3},
+		{4, 9},
+	}
+
+	for _, test := range tests {
+		q := NewQueue(test.size)
+		for i := 0; i < test.count; i++ {
+			q.Push(Vertex{ID: strconv.Itoa(i)})
+		}
+		if q.Len() != test.count {
+			t.Errorf("Len = %d, want %d", q.Len(), test.count)
+		}
+		for i := 0; i < test.count; i++ {
+			if v := q.Pop(); v.ID != strconv.Itoa(i) {
+				t.Errorf("size %d: Pop = %s, want %d", test.size, v.ID, i)
+			}
+		}
+	}
+}
+
+// TestQueueWraparound grows the queue after its head has moved.
+func TestQueueWraparound(t *testing.T) {
+	q := NewQueue(3)
+	next, want := 0, 0
+	for round := 0; round < 4; round++ {
+		for i := 0; i < 3; i++ {
+			q.Push(Vertex{ID: strconv.Itoa(next)})
+			next++
+		}
+		for i := 0; i < 2; i++ {
+			if v := q.Pop(); v.ID != strconv.Itoa(want) {
+				t.Fatalf("Pop = %s, want %d", v.ID, want)
+			}
+			want++
+		}
+	}
+	if q.Len() != next-want {
+		t.Errorf("Len = %d, want %d", q.Len(), next-want)
+	}
+}
+
+func TestVertexStack(t *testing.T) {
+	var s VertexStack
+	for i := 0; i < 3; i++ {
+		s.Push(Vertex{ID: strconv.Itoa(i)})
+	}
+	for i := 2; i >= 0; i-- {
+		if v := s.Pop(); v.ID != strconv.Itoa(i) {
+			t.Errorf("Pop = %s, want %d", v.ID, i)
+		}
+	}
+	if s.Len() != 0 {
+		t.Errorf("Len = %d after popping everything, want 0", s.Len())
+	}
+}
+
+func TestEdgeAttribute(t *testing.T) {
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{WeightAttribute, true},
+		{CapacityAttribute, true},
+		{CostAttribute, true},
+		{"label", false},
+	}
+
+	for i, test := range tests {
+		var e Edge
+		value := int64(i + 1)
+		if ok := e.SetAttribute(test.name, value); ok != test.ok {
+			t.Errorf("SetAttribute(%s) = %t, want %t", test.name, ok, test.ok)
+		}
+		got, ok := e.Attribute(test.name)
+		if ok != test.ok || (ok && got != value) {
+			t.Errorf("Attribute(%s) = %d, %t, want %d, %t", test.name, got, ok, value, test.ok)
+		}
+
+		// Setting one field leaves the others alone
+		var total int64
+		for _, other := range []int64{e.Weight, e.Capacity, e.Cost} {
+			total += other
+		}
+		if test.ok && total != value {
+			t.Errorf("SetAttribute(%s) changed other fields of %+v", test.name, e)
+		}
+	}
+}
+
+// checkEdges checks the edge enumeration methods of g against
+// each other, for a graph with the given number of edges.
+func checkEdges(t *testing.T, name string, g Graph, count int) {
+	t.Helper()
+	if g.EdgeCount() != count || len(g.Edges()) != count {
+		t.Errorf("%s: EdgeCount = %d and Edges has %d, want %d", name, g.EdgeCount(), len(g.Edges()), count)
+	}
+
+	seen := make(map[Edge]bool)
+	for _, e := range g.Edges() {
+		if seen[e] {
+			t.Errorf("%s: Edges returns %v twice", name, e)
+		}
+		seen[e] = true
+	}
+
+	degrees := 0
+	for _, v := range g.Vertices() {
+		degrees += g.Degree(v)
+		for _, e := range g.OutEdges(v) {
+			if e.Start != v {
+				t.Errorf("%s: OutEdges(%s) returns %v", name, v.ID, e)
+			}
+		}
+		for _, e := range g.InEdges(v) {
+			if e.End != v {
+				t.Errorf("%s: InEdges(%s) returns %v", name, v.ID, e)
+			}
+		}
+	}
+	if degrees != 2*count {
+		t.Errorf("%s: degrees add up to %d, want %d", name, degrees, 2*count)
+	}
+}
+
+func TestEdgeEnumeration(t *testing.T) {
+	a, b, c := Vertex{ID: "a"}, Vertex{ID: "b"}, Vertex{ID: "c"}
+	edges := []Edge{
+		{Start: a, End: b, ID: "ab"},
+		{Start: b, End: c, ID: "bc"},
+		{Start: c, End: a, ID: "ca"},
+		{Start: a, End: a, ID: "aa"},
+		{Start: b, End: a, ID: "ba"},
+	}
+
+	directed, undirected := &DirectedGraph{}, &UndirectedGraph{}
+	for _, e := range edges {
+		directed.AddEdge(e)
+		undirected.AddEdge(e)
+	}
+	checkEdges(t, "directed", directed, len(edges))
+	checkEdges(t, "undirected", undirected, len(edges))
+
+	tests := []struct {
+		name         string
+		g            Graph
+		v            Vertex
+		out, in, deg int
+	}{
+		{"directed", directed, a, 2, 3, 5},
+		{"directed", directed, c, 1, 1, 2},
+		{"undirected", undirected, a, 4, 4, 5},
+		{"undirected", undirected, c, 2, 2, 2},
+	}
+	for _, test := range tests {
+		if out, in, deg := len(test.g.OutEdges(test.v)), len(test.g.InEdges(test.v)), test.g.Degree(test.v); out != test.out || in != test.in || deg != test.deg {
+			t.Errorf("%s: %s has %d out edges, %d in edges and degree %d, want %d, %d, %d", test.name, test.v.ID, out, in, deg, test.out, test.in, test.deg)
+		}
+	}
+
+	for _, name := range []string{"benchmark1.csv", "benchmark3.csv", "edges_3_31601.csv"} {
+		directed := loadTestGraph(t, name)
+		undirected, _, err := LoadUndirectedGraph(filepath.Join("csv_files", name), LoadOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkEdges(t, name, directed, directed.EdgeCount())
+		checkEdges(t, name, undirected, undirected.EdgeCount())
+	}
+}
+
+func TestMultigraph(t *testing.T) {
+	a, b := Vertex{ID: "a"}, Vertex{ID: "b"}
+
+	tests := []struct {
+		name             string
+		edges            []Edge
+		simple, multiple int // Expected EdgeCount without and with multigraph
+	}{
+		{"parallel edges", []Edge{{Start: a, End: b, ID: "1"}, {Start: a, End: b, ID: "2"}}, 2, 2},
+		{"same ID", []Edge{{Start: a, End: b, Weight: 1, ID: "1"}, {Start: a, End: b, Weight: 2, ID: "1"}}, 2, 1},
+		{"same ID elsewhere", []Edge{{Start: a, End: b, ID: "1"}, {Start: b, End: b, ID: "1"}}, 2, 1},
+		{"identical", []Edge{{Start: a, End: b, ID: "1"}, {Start: a, End: b, ID: "1"}}, 1, 1},
+		{"without IDs", []Edge{{Start: a, End: b, Weight: 1}, {Start: a, End: b, Weight: 2}, {Start: a, End: b, Weight: 1}}, 2, 2},
+	}
+
+	for _, test := range tests {
+		graphs := []struct {
+			kind  string
+			g     interface{ AddEdge(Edge) }
+			count int
+		}{
+			{"directed", &DirectedGraph{}, test.simple},
+			{"undirected", &UndirectedGraph{}, test.simple},
+			{"directed multigraph", NewDirectedMultigraph(), test.multiple},
+			{"undirected multigraph", NewUndirectedMultigraph(), test.multiple},
+		}
+		for _, h := range graphs {
+			for _, e := range test.edges {
+				h.g.AddEdge(e)
+			}
+			if got := h.g.(Graph).EdgeCount(); got != h.count {
+				t.Errorf("%s: %s has %d edges, want %d", test.name, h.kind, got, h.count)
+			}
+		}
+	}
+}
+
+func TestLoadMultigraph(t *testing.T) {
+	csv := "Vertex1\tVertex2\tweight\tid\na\tb\t1\tx\na\tb\t2\tx\nb\ta\t3\ty\n"
+
+	for _, multigraph := range []bool{false, true} {
+		opts := LoadOptions{Multigraph: multigraph}
+		directed, _, err := ReadDirectedGraph(strings.NewReader(csv), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		undirected, _, err := ReadUndirectedGraph(strings.NewReader(csv), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := 3
+		if multigraph {
+			want = 2
+		}
+		for _, g := range []interface {
+			Graph
+			IsMultigraph() bool
+		}{directed, undirected} {
+			if g.IsMultigraph() != multigraph || g.EdgeCount() != want {
+				t.Errorf("Multigraph %t: read a multigraph %t with %d edges, want %d", multigraph, g.IsMultigraph(), g.EdgeCount(), want)
+			}
+		}
+	}
+}
+
+func TestGenericGraphs(t *testing.T) {
+	type E = EdgeOf[int, float64]
+	v := func(id int) VertexOf[int] { return VertexOf[int]{ID: id} }
+	edges := []E{
+		{Start: v(1), End: v(2), Weight: 0.5, Capacity: 1.5},
+		{Start: v(2), End: v(3), Weight: 0.25, Capacity: 0.75},
+		{Start: v(1), End: v(3), Weight: 1, Capacity: 0.5},
+	}
+
+	var d DirectedGraphOf[int, float64]
+	var u UndirectedGraphOf[int, float64]
+	for _, e := range edges {
+		d.AddEdge(e)
+		u.AddEdge(e)
+	}
+
+	tree, err := d.ShortestPaths(v(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dist, _ := tree.DistanceTo(v(3)); dist != 0.75 {
+		t.Errorf("distance from 1 to 3 = %g, want 0.75", dist)
+	}
+	if _, flow := d.Dinic(v(1), v(3)); flow != 1.25 {
+		t.Errorf("max flow from 1 to 3 = %g, want 1.25", flow)
+	}
+
+	var total float64
+	for _, e := range u.PrimMST(v(3)) {
+		total += e.Weight
+	}
+	if total != 0.75 {
+		t.Errorf("minimum spanning tree weighs %g, want 0.75", total)
+	}
+}
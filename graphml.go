@@ -0,0 +1,267 @@
+package graph
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// graphMLDocument and the types below describe the parts of a GraphML
+// file used by WriteGraphML and the GraphML reader.
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr,omitempty"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID      string `xml:"id,attr"`
+	For     string `xml:"for,attr"`
+	Name    string `xml:"attr.name,attr"`
+	Type    string `xml:"attr.type,attr"`
+	Default string `xml:"default,omitempty"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr,omitempty"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr,omitempty"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// graphMLType returns the GraphML attr.type of an attribute value.
+func graphMLType(value any) string {
+	switch value.(type) {
+	case int64:
+		return "long"
+	case float64:
+		return "double"
+	case bool:
+		return "boolean"
+	}
+	return "string"
+}
+
+// addGraphMLTypes records the types of the attributes in types. For
+// attributes of mixed types it falls back to "double" if they are all
+// numbers, and to "string" otherwise.
+func addGraphMLTypes(types map[string]string, attributes Attributes) {
+	numeric := map[string]bool{"long": true, "double": true}
+	for name, value := range attributes {
+		t, seen := types[name]
+		switch valueType := graphMLType(value); {
+		case !seen:
+			types[name] = valueType
+		case t == valueType:
+		case numeric[t] && numeric[valueType]:
+			types[name] = "double"
+		default:
+			types[name] = "string"
+		}
+	}
+}
+
+// declare adds a key for each of the named attributes, and returns
+// the IDs of the keys.
+func (doc *graphMLDocument) declare(kind string, names []string, types map[string]string) map[string]string {
+	ids := make(map[string]string, len(names))
+	for _, name := range names {
+		ids[name] = fmt.Sprintf("d%d", len(doc.Keys))
+		doc.Keys = append(doc.Keys, graphMLKey{ID: ids[name], For: kind, Name: name, Type: types[name]})
+	}
+	return ids
+}
+
+// WriteGraphML writes g to w as GraphML. The weight, capacity and cost
+// of the edges and the attributes of the vertices and edges are
+// declared as keys, typed after their values. Edge IDs are kept as the
+// id of the edge elements.
+func WriteGraphML(w io.Writer, g Graph) error {
+	return WriteGraphMLOf[int64](w, g)
+}
+
+// WriteGraphMLOf writes g to w like WriteGraphML, for graphs of any
+// weight type. The weight, capacity and cost keys are declared as
+// doubles if W is a floating point type.
+func WriteGraphMLOf[W Number](w io.Writer, g GraphOf[string, W]) error {
+	numberType := "long"
+	if isFloat[W]() {
+		numberType = "double"
+	}
+	doc := graphMLDocument{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: WeightAttribute, For: "edge", Name: WeightAttribute, Type: numberType},
+			{ID: CapacityAttribute, For: "edge", Name: CapacityAttribute, Type: numberType},
+			{ID: CostAttribute, For: "edge", Name: CostAttribute, Type: numberType},
+		},
+		Graph: graphMLGraph{ID: "G", EdgeDefault: "undirected"},
+	}
+	if isDirected(g) {
+		doc.Graph.EdgeDefault = "directed"
+	}
+
+	vertexTypes, edgeTypes := make(map[string]string), make(map[string]string)
+	for _, v := range g.Vertices() {
+		addGraphMLTypes(vertexTypes, g.VertexAttributes(v))
+	}
+	for _, e := range g.Edges() {
+		addGraphMLTypes(edgeTypes, g.EdgeAttributes(e))
+	}
+	vertexNames, edgeNames := attributeNames(g)
+	vertexKeys := doc.declare("node", vertexNames, vertexTypes)
+	edgeKeys := doc.declare("edge", edgeNames, edgeTypes)
+
+	for _, v := range g.Vertices() {
+		node := graphMLNode{ID: v.ID}
+		node.Data = appendGraphMLData(node.Data, g.VertexAttributes(v), vertexNames, vertexKeys)
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node)
+	}
+	for _, e := range g.Edges() {
+		edge := graphMLEdge{ID: e.ID, Source: e.Start.ID, Target: e.End.ID}
+		edge.Data = append(edge.Data, graphMLData{Key: WeightAttribute, Value: formatNumber(e.Weight)})
+		if e.Capacity != 0 {
+			edge.Data = append(edge.Data, graphMLData{Key: CapacityAttribute, Value: formatNumber(e.Capacity)})
+		}
+		if e.Cost != 0 {
+			edge.Data = append(edge.Data, graphMLData{Key: CostAttribute, Value: formatNumber(e.Cost)})
+		}
+		edge.Data = appendGraphMLData(edge.Data, g.EdgeAttributes(e), edgeNames, edgeKeys)
+		doc.Graph.Edges = append(doc.Graph.Edges, edge)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// appendGraphMLData appends the named attributes which are set
+// to data, using the given key IDs.
+func appendGraphMLData(data []graphMLData, attributes Attributes, names []string, keys map[string]string) []graphMLData {
+	for _, name := range names {
+		if value, ok := attributes[name]; ok {
+			data = append(data, graphMLData{Key: keys[name], Value: FormatAttribute(value)})
+		}
+	}
+	return data
+}
+
+// graphMLValue converts the text of a data element to the attribute
+// type matching the attr.type of its key.
+func graphMLValue(keyType, s string) any {
+	s = strings.TrimSpace(s)
+	switch keyType {
+	case "int", "long":
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+	case "float", "double":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case "string":
+		return s
+	}
+	return ParseAttribute(s)
+}
+
+// readGraphML reads the first graph of a GraphML document. The data
+// of nodes and edges is kept as attributes named by the attr.name of
+// their keys (or else the key ID) and typed after their attr.type,
+// except for the weight, capacity and cost of edges, which are read
+// into the Edge fields. Keys with a default apply to every node or
+// edge without data for them.
+func (gr *graphReader[W]) readGraphML(r io.Reader) error {
+	var doc graphMLDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		var syntaxErr *xml.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return &ParseError{Line: syntaxErr.Line, Reason: syntaxErr.Msg}
+		}
+		return err
+	}
+
+	keys := make(map[string]graphMLKey, len(doc.Keys))
+	for _, key := range doc.Keys {
+		if key.Name == "" {
+			key.Name = key.ID
+		}
+		keys[key.ID] = key
+	}
+
+	// attributes collects the data of an element of the given kind,
+	// starting from the defaults of the keys.
+	attributes := func(kind string, data []graphMLData) Attributes {
+		values := make(Attributes)
+		for _, key := range doc.Keys {
+			if key.Default != "" && (key.For == kind || key.For == "all") {
+				values[keys[key.ID].Name] = graphMLValue(key.Type, key.Default)
+			}
+		}
+		for _, d := range data {
+			key, ok := keys[d.Key]
+			if !ok {
+				key = graphMLKey{Name: d.Key}
+			}
+			values[key.Name] = graphMLValue(key.Type, d.Value)
+		}
+		return values
+	}
+
+	for _, node := range doc.Graph.Nodes {
+		v := Vertex{ID: node.ID}
+		gr.g.AddVertex(v)
+		gr.setVertexAttributes(v, attributes("node", node.Data))
+	}
+
+	for _, edge := range doc.Graph.Edges {
+		e := EdgeOf[string, W]{Start: Vertex{ID: edge.Source}, End: Vertex{ID: edge.Target}, Weight: -1, ID: edge.ID}
+		values := attributes("edge", edge.Data)
+
+		if err := takeEdgeFields(&e, values); err != nil {
+			if err := gr.skip(&ParseError{Reason: fmt.Sprintf("edge %s -> %s: %s", edge.Source, edge.Target, err)}); err != nil {
+				return err
+			}
+			continue
+		}
+		if e.ID == "" {
+			e.ID = gr.nextID()
+		}
+		gr.g.AddEdge(e)
+		gr.setEdgeAttributes(e, values)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestWriteGraphML(t *testing.T) {
+	g := &UndirectedGraph{}
+	buildAttributeGraph(g)
+
+	var b strings.Builder
+	if err := WriteGraphML(&b, g); err != nil {
+		t.Fatalf("WriteGraphML failed: %s", err)
+	}
+	var doc graphMLDocument
+	if err := xml.Unmarshal([]byte(b.String()), &doc); err != nil {
+		t.Fatalf("WriteGraphML wrote invalid XML: %s\n%s", err, b.String())
+	}
+
+	if doc.Graph.EdgeDefault != "undirected" {
+		t.Errorf("edgedefault = %q, want undirected", doc.Graph.EdgeDefault)
+	}
+	if len(doc.Graph.Nodes) != g.VertexCount() || len(doc.Graph.Edges) != g.EdgeCount() {
+		t.Fatalf("wrote %d nodes and %d edges, want %d and %d", len(doc.Graph.Nodes), len(doc.Graph.Edges), g.VertexCount(), g.EdgeCount())
+	}
+	if edge := doc.Graph.Edges[0]; edge.ID != "007" || edge.Source != "a" || edge.Target != "b b" {
+		t.Errorf("first edge is %+v", edge)
+	}
+
+	types := make(map[string]string)
+	for _, key := range doc.Keys {
+		types[key.For+" "+key.Name] = key.Type
+	}
+	for key, want := range map[string]string{
+		"edge weight": "long",
+		"edge label":  "string",
+		"edge rank":   "long",
+		"node x":      "double",
+		"node zip":    "string",
+		"node seen":   "boolean",
+	} {
+		if types[key] != want {
+			t.Errorf("key %s has type %q, want %q", key, types[key], want)
+		}
+	}
+}
+
+func TestReadGraphML(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="label" attr.type="string"/>
+  <key id="d1" for="node" attr.name="size" attr.type="double"><default>1.5</default></key>
+  <key id="d2" for="edge" attr.name="weight" attr.type="double"/>
+  <key id="d3" for="edge" attr.name="kind" attr.type="string"/>
+  <key id="d4" for="edge" attr.name="count" attr.type="int"/>
+  <graph id="G" edgedefault="directed">
+    <node id="n0"><data key="d0">007</data></node>
+    <node id="n1"><data key="d1">2</data></node>
+    <node id="n2"/>
+    <edge id="007" source="n0" target="n1"><data key="d2">3.0</data><data key="d3">1</data></edge>
+    <edge source="n1" target="n2"><data key="d4">5</data></edge>
+    <edge source="n2" target="n0"><data key="d2">0.5</data></edge>
+  </graph>
+</graphml>`
+
+	g, warnings := readTest(t, input, LoadOptions{Format: FormatGraphML})
+	if got, want := edgeSummary(g), "n0>n1:3:007 n1>n2:-1:e1"; got != want {
+		t.Errorf("read edges %s, want %s", got, want)
+	}
+	if len(warnings) != 1 || warnings[0].Reason == "" {
+		t.Errorf("warnings = %v, want one for the fractional weight", warnings)
+	}
+
+	tests := []struct {
+		attributes Attributes
+		name       string
+		want       any
+	}{
+		{g.VertexAttributes(Vertex{ID: "n0"}), "label", "007"},
+		{g.VertexAttributes(Vertex{ID: "n0"}), "size", 1.5},
+		{g.VertexAttributes(Vertex{ID: "n1"}), "size", 2.0},
+		{g.EdgeAttributes(g.Edges()[0]), "kind", "1"},
+		{g.EdgeAttributes(g.Edges()[1]), "count", int64(5)},
+	}
+	for _, test := range tests {
+		if got := test.attributes[test.name]; got != test.want {
+			t.Errorf("%s = %#v, want %#v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestGraphMLRoundTrip(t *testing.T) {
+	g := &DirectedGraph{}
+	buildAttributeGraph(g)
+
+	var b strings.Builder
+	if err := WriteGraphML(&b, g); err != nil {
+		t.Fatal(err)
+	}
+	read, _ := readTest(t, b.String(), LoadOptions{Format: FormatGraphML, Strict: true})
+	if got, want := describeGraph(read), describeGraph(g); got != want {
+		t.Errorf("read back\n%s\nas\n%s\nwant\n%s", b.String(), got, want)
+	}
+}
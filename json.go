@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonGraph is the node-link format written by WriteJSON, as used by
+// e.g. NetworkX and D3.
+type jsonGraph struct {
+	Directed   bool             `json:"directed"`
+	Multigraph bool             `json:"multigraph"`
+	Graph      map[string]any   `json:"graph"`
+	Nodes      []map[string]any `json:"nodes"`
+	Links      []map[string]any `json:"links"`
+}
+
+// WriteJSON writes g to w in the JSON node-link format. Every node
+// holds its "id" and attributes, and every link its "source", "target",
+// "id", "weight", "capacity" and "cost" together with its attributes.
+// Attributes named like one of these fields are left out.
+func WriteJSON(w io.Writer, g Graph) error {
+	return WriteJSONOf[int64](w, g)
+}
+
+// WriteJSONOf writes g to w like WriteJSON, for graphs of any weight
+// type.
+func WriteJSONOf[W Number](w io.Writer, g GraphOf[string, W]) error {
+	doc := jsonGraph{
+		Directed:   isDirected(g),
+		Multigraph: isMultigraph(g),
+		Graph:      map[string]any{},
+		Nodes:      []map[string]any{},
+		Links:      []map[string]any{},
+	}
+
+	for _, v := range g.Vertices() {
+		node := map[string]any{"id": v.ID}
+		doc.Nodes = append(doc.Nodes, addJSONAttributes(node, g.VertexAttributes(v)))
+	}
+	for _, e := range g.Edges() {
+		link := map[string]any{
+			"source":          e.Start.ID,
+			"target":          e.End.ID,
+			"id":              e.ID,
+			WeightAttribute:   e.Weight,
+			CapacityAttribute: e.Capacity,
+			CostAttribute:     e.Cost,
+		}
+		doc.Links = append(doc.Links, addJSONAttributes(link, g.EdgeAttributes(e)))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// addJSONAttributes adds the attributes to object, without
+// replacing any of its fields.
+func addJSONAttributes(object map[string]any, attributes Attributes) map[string]any {
+	for name, value := range attributes {
+		if _, taken := object[name]; !taken {
+			object[name] = value
+		}
+	}
+	return object
+}
@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWriteJSON(t *testing.T) {
+	g := NewDirectedMultigraph()
+	buildAttributeGraph(g)
+
+	var b strings.Builder
+	if err := WriteJSON(&b, g); err != nil {
+		t.Fatalf("WriteJSON failed: %s", err)
+	}
+	var doc jsonGraph
+	if err := json.Unmarshal([]byte(b.String()), &doc); err != nil {
+		t.Fatalf("WriteJSON wrote invalid JSON: %s\n%s", err, b.String())
+	}
+
+	if !doc.Directed || !doc.Multigraph {
+		t.Errorf("directed and multigraph are %t and %t, want true", doc.Directed, doc.Multigraph)
+	}
+	if len(doc.Nodes) != g.VertexCount() || len(doc.Links) != g.EdgeCount() {
+		t.Fatalf("wrote %d nodes and %d links, want %d and %d", len(doc.Nodes), len(doc.Links), g.VertexCount(), g.EdgeCount())
+	}
+
+	tests := []struct {
+		object map[string]any
+		name   string
+		want   any
+	}{
+		{doc.Nodes[0], "id", "a"},
+		{doc.Nodes[0], "zip", "01234"},
+		{doc.Nodes[0], "x", 1.5},
+		{doc.Links[0], "source", "a"},
+		{doc.Links[0], "target", "b b"},
+		{doc.Links[0], "id", "007"},
+		{doc.Links[0], "weight", 2.0},
+		{doc.Links[0], "capacity", 3.0},
+		{doc.Links[0], "label", `x "y", z`},
+		{doc.Links[1], "cost", 4.0},
+		{doc.Links[1], "label", "true"},
+	}
+	for _, test := range tests {
+		if got := test.object[test.name]; got != test.want {
+			t.Errorf("%s of %v = %#v, want %#v", test.name, test.object["id"], got, test.want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package graph
+
+// blossom holds the state of Edmonds' blossom algorithm. Vertices are
+// referred to by their index in the graph's vertices.
+type blossom struct {
+	adj       [][]int // [Vertex]Neighbours
+	mate      []int   // [Vertex]Matched vertex, -1 if unmatched
+	parent    []int   // [Vertex]Previous vertex on the alternating path, -1 if none
+	base      []int   // [Vertex]Base of the blossom the vertex belongs to
+	used      []bool  // [Vertex]Whether the vertex is even in the current tree
+	inBlossom []bool  // [Base]Whether the blossom is part of the one being contracted
+	seen      []bool  // Scratch space for lca
+	queue     []int
+}
+
+// BlossomMatching finds a maximum-cardinality matching using Edmonds'
+// blossom algorithm, that is as many edges as possible of which no two
+// share a vertex. Unlike GreedyMatching it always finds a maximum
+// matching, also in disconnected graphs, and it is deterministic.
+// Self loops are never part of a matching. The edges are returned in
+// the order they were added to the graph. It runs in O(V^3) time.
+func (g *UndirectedGraphOf[K, W]) BlossomMatching() EdgesOf[K, W] {
+	n := len(g.vertices)
+	b := &blossom{
+		adj:       make([][]int, n),
+		mate:      make([]int, n),
+		parent:    make([]int, n),
+		base:      make([]int, n),
+		used:      make([]bool, n),
+		inBlossom: make([]bool, n),
+		seen:      make([]bool, n),
+	}
+	for _, e := range g.edgeList {
+		u, v := g.index[e.Start], g.index[e.End]
+		if u != v {
+			b.adj[u] = append(b.adj[u], v)
+			b.adj[v] = append(b.adj[v], u)
+		}
+	}
+	for v := range b.mate {
+		b.mate[v] = -1
+	}
+
+	// Start off from a greedy matching, which saves most searches
+	for v := range b.adj {
+		if b.mate[v] != -1 {
+			continue
+		}
+		for _, to := range b.adj[v] {
+			if b.mate[to] == -1 {
+				b.mate[v], b.mate[to] = to, v
+				break
+			}
+		}
+	}
+
+	for v := range b.adj {
+		if b.mate[v] == -1 {
+			if end := b.findPath(v); end != -1 {
+				b.augment(end)
+			}
+		}
+	}
+
+	return matchedEdges(g.edgeList, g.index, b.mate)
+}
+
+// matchedEdges returns an edge for every matched pair of vertices in
+// mate, the first one in edges which connects them.
+func matchedEdges[K comparable, W Number](edges []EdgeOf[K, W], index map[VertexOf[K]]int, mate []int) EdgesOf[K, W] {
+	taken := make([]bool, len(mate))
+	var matching EdgesOf[K, W]
+	for _, e := range edges {
+		u, v := index[e.Start], index[e.End]
+		if u != v && mate[u] == v && !taken[u] {
+			taken[u], taken[v] = true, true
+			matching = append(matching, e)
+		}
+	}
+	return matching
+}
+
+// findPath searches for an augmenting path from the unmatched vertex
+// root, contracting the blossoms it finds. It returns the unmatched
+// vertex the path ends in, or -1 if there is none. The path can then
+// be followed back through parent and mate.
+func (b *blossom) findPath(root int) int {
+	for v := range b.adj {
+		b.used[v] = false
+		b.parent[v] = -1
+		b.base[v] = v
+	}
+	b.used[root] = true
+	b.queue = append(b.queue[:0], root)
+
+	for head := 0; head < len(b.queue); head++ {
+		v := b.queue[head]
+		for _, to := range b.adj[v] {
+			if b.base[v] == b.base[to] || b.mate[v] == to {
+				continue
+			}
+			if to == root || b.mate[to] != -1 && b.parent[b.mate[to]] != -1 {
+				// An odd cycle, contract it into a blossom
+				base := b.lca(v, to)
+				for i := range b.inBlossom {
+					b.inBlossom[i] = false
+				}
+				b.markPath(v, base, to)
+				b.markPath(to, base, v)
+				for i := range b.adj {
+					if b.inBlossom[b.base[i]] {
+						b.base[i] = base
+						if !b.used[i] {
+							b.used[i] = true
+							b.queue = append(b.queue, i)
+						}
+					}
+				}
+			} else if b.parent[to] == -1 {
+				b.parent[to] = v
+				if b.mate[to] == -1 {
+					return to
+				}
+				b.used[b.mate[to]] = true
+				b.queue = append(b.queue, b.mate[to])
+			}
+		}
+	}
+
+	return -1
+}
+
+// lca returns the base of the blossom where the alternating
+// paths from the root to a and to b meet.
+func (b *blossom) lca(u, v int) int {
+	for i := range b.seen {
+		b.seen[i] = false
+	}
+	for {
+		u = b.base[u]
+		b.seen[u] = true
+		if b.mate[u] == -1 {
+			break // The root
+		}
+		u = b.parent[b.mate[u]]
+	}
+	for {
+		v = b.base[v]
+		if b.seen[v] {
+			return v
+		}
+		v = b.parent[b.mate[v]]
+	}
+}
+
+// markPath marks the blossoms on the path from v down to the
+// base of the new blossom, pointing the parents along it
+// towards child so the blossom can be walked either way.
+func (b *blossom) markPath(v, base, child int) {
+	for b.base[v] != base {
+		b.inBlossom[b.base[v]] = true
+		b.inBlossom[b.base[b.mate[v]]] = true
+		b.parent[v] = child
+		child = b.mate[v]
+		v = b.parent[b.mate[v]]
+	}
+}
+
+// augment flips the edges along the augmenting path ending in end,
+// growing the matching by one edge.
+func (b *blossom) augment(end int) {
+	for v := end; v != -1; {
+		previous := b.parent[v]
+		next := b.mate[previous]
+		b.mate[v], b.mate[previous] = previous, v
+		v = next
+	}
+}
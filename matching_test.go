@@ -0,0 +1,167 @@
+package graph
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// undirectedGraph returns a graph of the given "start end [weight]"
+// edges, with IDs e0, e1 and so on.
+func undirectedGraph(edges ...string) *UndirectedGraph {
+	g := &UndirectedGraph{}
+	for i, e := range edges {
+		fields := strings.Fields(e)
+		edge := Edge{Start: Vertex{ID: fields[0]}, End: Vertex{ID: fields[1]}, ID: "e" + strconv.Itoa(i)}
+		if len(fields) > 2 {
+			edge.Weight, _ = strconv.ParseInt(fields[2], 10, 64)
+		}
+		g.AddEdge(edge)
+	}
+	return g
+}
+
+// randomGraph returns a graph of n vertices and m random edges, which
+// may include self loops and parallel edges, weighted from -5 to 20.
+func randomGraph(r *rand.Rand, n, m int) *UndirectedGraph {
+	g := &UndirectedGraph{}
+	for i := 0; i < n; i++ {
+		g.AddVertex(Vertex{ID: strconv.Itoa(i)})
+	}
+	for i := 0; i < m; i++ {
+		g.AddEdge(Edge{
+			Start:  Vertex{ID: strconv.Itoa(r.Intn(n))},
+			End:    Vertex{ID: strconv.Itoa(r.Intn(n))},
+			Weight: int64(r.Intn(26) - 5),
+			ID:     "e" + strconv.Itoa(i),
+		})
+	}
+	return g
+}
+
+// petersenGraph returns the Petersen graph, which has a perfect
+// matching but needs blossoms to find it.
+func petersenGraph() *UndirectedGraph {
+	var edges []string
+	for i := 0; i < 5; i++ {
+		edges = append(edges,
+			strconv.Itoa(i)+" "+strconv.Itoa((i+1)%5),     // Outer cycle
+			strconv.Itoa(i)+" "+strconv.Itoa(i+5),         // Spokes
+			strconv.Itoa(i+5)+" "+strconv.Itoa((i+2)%5+5), // Inner star
+		)
+	}
+	return undirectedGraph(edges...)
+}
+
+// forEachMatching calls f with every matching of g, including the
+// empty one, its edges in the order of g.Edges().
+func forEachMatching(g *UndirectedGraph, f func(matching []Edge)) {
+	edges := g.Edges()
+	matched := make(map[Vertex]bool)
+	var matching []Edge
+	var next func(i int)
+	next = func(i int) {
+		if i == len(edges) {
+			f(matching)
+			return
+		}
+		next(i + 1)
+		if e := edges[i]; e.Start != e.End && !matched[e.Start] && !matched[e.End] {
+			matched[e.Start], matched[e.End] = true, true
+			matching = append(matching, e)
+			next(i + 1)
+			matching = matching[:len(matching)-1]
+			matched[e.Start], matched[e.End] = false, false
+		}
+	}
+	next(0)
+}
+
+// bruteForceMatchingSize returns the size of a maximum matching of g.
+func bruteForceMatchingSize(g *UndirectedGraph) int {
+	size := 0
+	forEachMatching(g, func(matching []Edge) {
+		if len(matching) > size {
+			size = len(matching)
+		}
+	})
+	return size
+}
+
+// checkMatching checks that matching is made of edges of g, in the
+// order they were added, of which no two share a vertex.
+func checkMatching(t *testing.T, name string, g *UndirectedGraph, matching Edges) {
+	t.Helper()
+	order := make(map[Edge]int)
+	for i, e := range g.Edges() {
+		order[e] = i
+	}
+	matched := make(map[Vertex]bool)
+	for i, e := range matching {
+		position, ok := order[e]
+		if !ok {
+			t.Errorf("%s: matched edge %v is not in the graph", name, e)
+			continue
+		}
+		if i > 0 && position < order[matching[i-1]] {
+			t.Errorf("%s: matched edge %s comes before %s in the graph", name, e.ID, matching[i-1].ID)
+		}
+		if e.Start == e.End || matched[e.Start] || matched[e.End] {
+			t.Errorf("%s: matched edge %s shares a vertex with another", name, e.ID)
+		}
+		matched[e.Start], matched[e.End] = true, true
+	}
+}
+
+func TestBlossomMatching(t *testing.T) {
+	tests := []struct {
+		name string
+		g    *UndirectedGraph
+		size int
+	}{
+		{"empty", &UndirectedGraph{}, 0},
+		{"single edge", undirectedGraph("a b"), 1},
+		{"self loop", undirectedGraph("a a"), 0},
+		{"path", undirectedGraph("a b", "b c", "c d"), 2},
+		{"star", undirectedGraph("a b", "a c", "a d"), 1},
+		{"triangle", undirectedGraph("a b", "b c", "c a"), 1},
+		{"odd cycle", undirectedGraph("a b", "b c", "c d", "d e", "e a"), 2},
+		{"odd cycle with a stem", undirectedGraph("a b", "b c", "c d", "d e", "e a", "a f"), 3},
+		// The greedy start matches b c and e f, leaving a and d to be
+		// matched by a path through the blossoms a b c and d e f
+		{"two blossoms", undirectedGraph("b c", "e f", "a b", "a c", "d e", "d f", "c e"), 3},
+		{"disconnected", undirectedGraph("a b", "c d", "e f", "f g"), 3},
+		{"parallel edges", undirectedGraph("a b 1", "a b 2"), 1},
+		{"petersen", petersenGraph(), 5},
+	}
+
+	for _, test := range tests {
+		matching := test.g.BlossomMatching()
+		if len(matching) != test.size {
+			t.Errorf("%s: matched %d edges %v, want %d", test.name, len(matching), matching, test.size)
+		}
+		checkMatching(t, test.name, test.g, matching)
+	}
+}
+
+func TestBlossomMatchingBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		g := randomGraph(r, 2+r.Intn(9), r.Intn(16))
+		name := "random graph " + strconv.Itoa(i)
+
+		matching := g.BlossomMatching()
+		checkMatching(t, name, g, matching)
+		if want := bruteForceMatchingSize(g); len(matching) != want {
+			t.Errorf("%s: matched %d edges, want %d\n%v", name, len(matching), want, g.Edges())
+		}
+	}
+}
+
+func BenchmarkBlossomMatching(b *testing.B) {
+	g := randomGraph(rand.New(rand.NewSource(1)), 1000, 3000)
+	for i := 0; i < b.N; i++ {
+		g.BlossomMatching()
+	}
+}
@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxGrowEntries is the most entries a Matrix Market reader makes room
+// for up front.
+const maxGrowEntries = 1 << 20
+
+// readMatrixMarket reads a Matrix Market coordinate matrix. The graph
+// gets the vertices "1" to "n", n being the larger of the row and
+// column counts, and an edge from vertex i to vertex j for entry (i, j)
+// weighted by its value, or -1 for pattern matrices. If W is an integer
+// type, the values of real matrices must be whole numbers too, and
+// entries with fractional values are skipped as invalid. The entries
+// of symmetric (and skew-symmetric) matrices stand for their mirror
+// image too, which a DirectedGraph gets as an edge in the opposite
+// direction (with the weight negated). The edges are numbered in the
+// order they are read.
+func (gr *graphReader[W]) readMatrixMarket(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+
+	line := 0
+	// next returns the next line which is not a comment
+	next := func() (string, bool) {
+		for scanner.Scan() {
+			line++
+			text := strings.TrimSpace(scanner.Text())
+			if text != "" && !strings.HasPrefix(text, "%") {
+				return text, true
+			}
+		}
+		return "", false
+	}
+
+	// The banner, e.g. %%MatrixMarket matrix coordinate real general
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		return &ParseError{Line: 1, Reason: "missing %%MatrixMarket header"}
+	}
+	line++
+	banner := strings.Fields(strings.ToLower(scanner.Text()))
+	if len(banner) != 5 || banner[0] != "%%matrixmarket" {
+		return &ParseError{Line: line, Reason: "missing %%MatrixMarket header"}
+	}
+	if banner[1] != "matrix" || banner[2] != "coordinate" {
+		return &ParseError{Line: line, Reason: fmt.Sprintf("unsupported %s %s, only coordinate matrices can be read", banner[1], banner[2])}
+	}
+	field, symmetry := banner[3], banner[4]
+	switch field {
+	case "real", "double", "integer", "pattern":
+	default:
+		return &ParseError{Line: line, Reason: fmt.Sprintf("unsupported field %q", field)}
+	}
+	switch symmetry {
+	case "general", "symmetric", "skew-symmetric", "hermitian":
+	default:
+		return &ParseError{Line: line, Reason: fmt.Sprintf("unsupported symmetry %q", symmetry)}
+	}
+
+	// The size line: rows, columns and number of entries
+	text, ok := next()
+	if !ok {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		return &ParseError{Line: line, Reason: "missing size line"}
+	}
+	var rows, columns, entries int
+	if _, err := fmt.Sscan(text, &rows, &columns, &entries); err != nil {
+		return &ParseError{Line: line, Reason: "invalid size line: " + err.Error()}
+	}
+	if rows < 0 || columns < 0 || entries < 0 {
+		return &ParseError{Line: line, Reason: "invalid size line: negative size"}
+	}
+	n := rows
+	if columns > n {
+		n = columns
+	}
+	_, directed := gr.g.(*DirectedGraphOf[string, W])
+	// Directed graphs get the mirror image of symmetric entries as an
+	// edge of its own. The entry count is not trusted beyond a limit,
+	// as the file may end well before it.
+	edges := min(entries, maxGrowEntries)
+	if directed && symmetry != "general" {
+		edges *= 2
+	}
+	gr.g.Grow(n, edges)
+	for i := 1; i <= n; i++ {
+		gr.g.AddVertex(Vertex{ID: strconv.Itoa(i)})
+	}
+
+	width := 3
+	if field == "pattern" {
+		width = 2
+	}
+	for text, ok := next(); ok; text, ok = next() {
+		values := strings.Fields(text)
+		if len(values) != width {
+			if err := gr.skip(&ParseError{Line: line, Reason: fmt.Sprintf("expected %d values, got %d", width, len(values))}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		i, iErr := strconv.Atoi(values[0])
+		j, jErr := strconv.Atoi(values[1])
+		if iErr != nil || jErr != nil || i < 1 || i > rows || j < 1 || j > columns {
+			if err := gr.skip(&ParseError{Line: line, Reason: fmt.Sprintf("invalid entry (%s, %s) for a %dx%d matrix", values[0], values[1], rows, columns)}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		weight := W(-1)
+		if width == 3 {
+			var err error
+			if weight, err = parseNumber[W](values[2]); err != nil {
+				reason := "invalid value: " + err.Error()
+				if _, floatErr := strconv.ParseFloat(values[2], 64); floatErr == nil {
+					reason += ", only whole numbers can be read as edge weights"
+				}
+				if err := gr.skip(&ParseError{Line: line, Column: 3, Reason: reason}); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		e := EdgeOf[string, W]{Start: Vertex{ID: strconv.Itoa(i)}, End: Vertex{ID: strconv.Itoa(j)}, Weight: weight, ID: gr.nextID()}
+		gr.g.AddEdge(e)
+		if directed && symmetry != "general" && i != j {
+			mirror := e.Reverse()
+			if symmetry == "skew-symmetric" && width == 3 {
+				mirror.Weight = -weight
+			}
+			mirror.ID = gr.nextID()
+			gr.g.AddEdge(mirror)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,126 @@
+package graph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadMatrixMarket(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		edges string
+	}{
+		{
+			"general",
+			"%%MatrixMarket matrix coordinate integer general\n% comment\n3 3 2\n1 2 5\n3 1 -2\n",
+			"1>2:5:e1 3>1:-2:e2",
+		},
+		{
+			"real",
+			"%%MatrixMarket matrix coordinate real general\n2 2 1\n1 2 4.0\n",
+			"1>2:4:e1",
+		},
+		{
+			"symmetric",
+			"%%MatrixMarket matrix coordinate integer symmetric\n2 2 2\n2 1 3\n2 2 1\n",
+			"1>2:3:e2 2>1:3:e1 2>2:1:e3",
+		},
+		{
+			"skew-symmetric",
+			"%%MatrixMarket matrix coordinate integer skew-symmetric\n2 2 1\n2 1 3\n",
+			"1>2:-3:e2 2>1:3:e1",
+		},
+		{
+			"explicit zero",
+			"%%MatrixMarket matrix coordinate integer general\n2 2 2\n1 2 0\n2 1 1\n",
+			"1>2:0:e1 2>1:1:e2",
+		},
+		{
+			"pattern",
+			"%%MatrixMarket matrix coordinate pattern general\n3 2 2\n1 2\n3 1\n",
+			"1>2:-1:e1 3>1:-1:e2",
+		},
+	}
+
+	for _, test := range tests {
+		g, warnings := readTest(t, test.input, LoadOptions{Format: FormatMatrixMarket, Strict: true})
+		if got := edgeSummary(g); got != test.edges {
+			t.Errorf("%s: read edges %s, want %s", test.name, got, test.edges)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("%s: got warnings %v", test.name, warnings)
+		}
+	}
+
+	// All vertices up to the larger dimension exist, even without entries
+	g, _ := readTest(t, "%%MatrixMarket matrix coordinate pattern general\n2 4 0\n", LoadOptions{Format: FormatMatrixMarket})
+	if got := len(g.Vertices()); got != 4 {
+		t.Errorf("a 2x4 matrix has %d vertices, want 4", got)
+	}
+}
+
+func TestReadMatrixMarketWarnings(t *testing.T) {
+	input := "%%MatrixMarket matrix coordinate real general\n3 3 4\n1 2 1.5\n1 2\n4 1 1\n2 3 2\n"
+	g, warnings := readTest(t, input, LoadOptions{Format: FormatMatrixMarket})
+
+	if got := edgeSummary(g); got != "2>3:2:e1" {
+		t.Errorf("read edges %s, want 2>3:2:e1", got)
+	}
+	if len(warnings) != 3 {
+		t.Fatalf("got warnings %v, want 3", warnings)
+	}
+	if w := warnings[0]; w.Line != 3 || w.Column != 3 || !strings.Contains(w.Reason, "only whole numbers") {
+		t.Errorf("warning for 1.5 is %v", w)
+	}
+	if w := warnings[1]; w.Line != 4 || w.Reason != "expected 3 values, got 2" {
+		t.Errorf("warning for a missing value is %v", w)
+	}
+	if w := warnings[2]; w.Line != 5 || w.Reason != "invalid entry (4, 1) for a 3x3 matrix" {
+		t.Errorf("warning for an entry out of range is %v", w)
+	}
+}
+
+func TestReadMatrixMarketErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		line   int
+		reason string
+	}{
+		{"empty", "", 1, "missing %%MatrixMarket header"},
+		{"no header", "3 3 1\n1 2 1\n", 1, "missing %%MatrixMarket header"},
+		{"array", "%%MatrixMarket matrix array real general\n", 1, "unsupported matrix array"},
+		{"complex", "%%MatrixMarket matrix coordinate complex general\n", 1, `unsupported field "complex"`},
+		{"symmetry", "%%MatrixMarket matrix coordinate real lower\n", 1, `unsupported symmetry "lower"`},
+		{"no size", "%%MatrixMarket matrix coordinate real general\n% comment\n", 2, "missing size line"},
+		{"bad size", "%%MatrixMarket matrix coordinate real general\n3 x 1\n", 2, "invalid size line"},
+		{"negative size", "%%MatrixMarket matrix coordinate real general\n3 3 -1\n", 2, "invalid size line: negative size"},
+		{"strict value", "%%MatrixMarket matrix coordinate real general\n2 2 1\n1 2 0.5\n", 3, "invalid value"},
+	}
+
+	for _, test := range tests {
+		_, _, err := ReadDirectedGraph(strings.NewReader(test.input), LoadOptions{Format: FormatMatrixMarket, Strict: true})
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s: got %v, want a *ParseError", test.name, err)
+			continue
+		}
+		if parseErr.Line != test.line || !strings.HasPrefix(parseErr.Reason, test.reason) {
+			t.Errorf("%s: got error on line %d: %s, want line %d: %s", test.name, parseErr.Line, parseErr.Reason, test.line, test.reason)
+		}
+	}
+}
+
+func TestReadMatrixMarketUndirected(t *testing.T) {
+	input := "%%MatrixMarket matrix coordinate integer symmetric\n2 2 1\n2 1 3\n"
+	g, _, err := ReadUndirectedGraph(strings.NewReader(input), LoadOptions{Format: FormatMatrixMarket, Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The entry already stands for both directions
+	if got := g.EdgeCount(); got != 1 {
+		t.Errorf("read %d edges, want 1", got)
+	}
+}
@@ -0,0 +1,285 @@
+package graph
+
+// residualArc is an arc in a residualGraph. Every edge of the original
+// graph gives rise to a forward arc holding its remaining capacity and
+// a backward arc holding the flow which can still be pushed back.
+type residualArc[W Number] struct {
+	to       int // Index of the vertex the arc leads to
+	reverse  int // Index of the opposite arc in adj[to]
+	capacity W   // Remaining capacity
+	cost     W   // Cost per unit of flow, negated for backward arcs
+	edge     int // Index of the original edge, -1 for backward arcs
+}
+
+// residualStep is the arc used to reach a vertex during a search.
+type residualStep struct {
+	from, arc int
+}
+
+// residualGraph is the residual network used by the flow algorithms.
+// Vertices are referred to by their index in vertices.
+type residualGraph[K comparable, W Number] struct {
+	vertices []VertexOf[K]
+	index    map[VertexOf[K]]int
+	adj      [][]residualArc[W]
+	edges    []EdgeOf[K, W] // The original edges
+	capacity []W            // The original capacity of every edge
+}
+
+func (d *DirectedGraphOf[K, W]) newResidualGraph() *residualGraph[K, W] {
+	r := &residualGraph[K, W]{
+		vertices: d.vertices,
+		index:    d.index,
+		adj:      make([][]residualArc[W], len(d.vertices)),
+	}
+
+	for _, u := range d.vertices {
+		for _, edge := range d.edges[u] {
+			r.addEdge(edge, edge.Capacity)
+		}
+	}
+
+	return r
+}
+
+// addEdge adds the forward and backward arcs of edge.
+func (r *residualGraph[K, W]) addEdge(edge EdgeOf[K, W], capacity W) {
+	if capacity < 0 {
+		capacity = 0
+	}
+	from, to := r.index[edge.Start], r.index[edge.End]
+	r.edges = append(r.edges, edge)
+	r.capacity = append(r.capacity, capacity)
+
+	forward := residualArc[W]{to: to, reverse: len(r.adj[to]), capacity: capacity, cost: edge.Cost, edge: len(r.edges) - 1}
+	backward := residualArc[W]{to: from, reverse: len(r.adj[from]), capacity: 0, cost: -edge.Cost, edge: -1}
+	if from == to {
+		// A self loop, the backward arc ends up after the forward one
+		forward.reverse++
+	}
+	r.adj[from] = append(r.adj[from], forward)
+	r.adj[to] = append(r.adj[to], backward)
+}
+
+// terminals looks up the indices of source and sink. It reports
+// false if either is missing or they are the same vertex, in
+// which case there can be no flow between them.
+func (r *residualGraph[K, W]) terminals(source, sink VertexOf[K]) (int, int, bool) {
+	s, ok := r.index[source]
+	if !ok {
+		return 0, 0, false
+	}
+	t, ok := r.index[sink]
+	if !ok || s == t {
+		return 0, 0, false
+	}
+
+	return s, t, true
+}
+
+// reachable returns which vertices can be reached from s
+// through arcs which still have free capacity.
+func (r *residualGraph[K, W]) reachable(s int) []bool {
+	visited := make([]bool, len(r.vertices))
+	visited[s] = true
+
+	Q := NewQueueOf[K](len(r.vertices))
+	Q.Push(r.vertices[s])
+	for Q.Len() > 0 {
+		u := r.index[Q.Pop()]
+		for _, arc := range r.adj[u] {
+			if arc.capacity > 0 && !visited[arc.to] {
+				visited[arc.to] = true
+				Q.Push(r.vertices[arc.to])
+			}
+		}
+	}
+
+	return visited
+}
+
+// push sends flow along the given arc, updating its reverse arc.
+func (r *residualGraph[K, W]) push(from, arc int, flow W) {
+	a := &r.adj[from][arc]
+	a.capacity -= flow
+	r.adj[a.to][a.reverse].capacity += flow
+}
+
+// flows returns the flow currently sent along every original edge.
+func (r *residualGraph[K, W]) flows() map[EdgeOf[K, W]]W {
+	flow := make(map[EdgeOf[K, W]]W, len(r.edges))
+	for u := range r.adj {
+		for _, arc := range r.adj[u] {
+			if arc.edge >= 0 {
+				flow[r.edges[arc.edge]] += r.capacity[arc.edge] - arc.capacity
+			}
+		}
+	}
+
+	return flow
+}
+
+// FindMaxFlow finds the maximum flow from source to sink, limited by
+// the Capacity of every edge. It returns the flow sent along every
+// edge together with the total flow. It is currently an alias for
+// EdmondsKarp.
+func (d *DirectedGraphOf[K, W]) FindMaxFlow(source, sink VertexOf[K]) (map[EdgeOf[K, W]]W, W) {
+	return d.EdmondsKarp(source, sink)
+}
+
+// EdmondsKarp finds the maximum flow from source to sink by repeatedly
+// augmenting the flow along the shortest path (in number of edges) with
+// free capacity in the residual graph, sending as much flow as the
+// bottleneck of the path allows. It runs in O(VE^2).
+// It returns the flow sent along every edge together with the total flow.
+func (d *DirectedGraphOf[K, W]) EdmondsKarp(source, sink VertexOf[K]) (map[EdgeOf[K, W]]W, W) {
+	r := d.newResidualGraph()
+	s, t, ok := r.terminals(source, sink)
+	if !ok {
+		return r.flows(), 0
+	}
+
+	maxFlow := r.edmondsKarp(s, t)
+	return r.flows(), maxFlow
+}
+
+// edmondsKarp saturates the residual graph with flow from s to t
+// and returns the amount of flow sent.
+func (r *residualGraph[K, W]) edmondsKarp(s, t int) W {
+	var maxFlow W
+	for {
+		// Breadth first search for the shortest augmenting path
+		parent := make([]residualStep, len(r.vertices))
+		visited := make([]bool, len(r.vertices))
+		visited[s] = true
+
+		Q := NewQueueOf[K](len(r.vertices))
+		Q.Push(r.vertices[s])
+		for Q.Len() > 0 && !visited[t] {
+			u := r.index[Q.Pop()]
+			for i, arc := range r.adj[u] {
+				if arc.capacity > 0 && !visited[arc.to] {
+					visited[arc.to] = true
+					parent[arc.to] = residualStep{u, i}
+					Q.Push(r.vertices[arc.to])
+				}
+			}
+		}
+
+		if !visited[t] {
+			break // No path with free capacity left.
+		}
+
+		// Find the bottleneck of the path...
+		bottleneck := r.adj[parent[t].from][parent[t].arc].capacity
+		for v := t; v != s; v = parent[v].from {
+			if c := r.adj[parent[v].from][parent[v].arc].capacity; c < bottleneck {
+				bottleneck = c
+			}
+		}
+		// ...and send that much flow along it.
+		for v := t; v != s; v = parent[v].from {
+			r.push(parent[v].from, parent[v].arc, bottleneck)
+		}
+		maxFlow += bottleneck
+	}
+
+	return maxFlow
+}
+
+// Dinic finds the maximum flow from source to sink using Dinic's
+// algorithm. Each phase builds a level graph of the shortest paths in
+// the residual graph and saturates it with a blocking flow found by
+// depth first search. It runs in O(V^2 E), and is usually much faster
+// than EdmondsKarp on large graphs.
+// It returns the flow sent along every edge together with the total flow.
+func (d *DirectedGraphOf[K, W]) Dinic(source, sink VertexOf[K]) (map[EdgeOf[K, W]]W, W) {
+	r := d.newResidualGraph()
+	s, t, ok := r.terminals(source, sink)
+	if !ok {
+		return r.flows(), 0
+	}
+
+	maxFlow := r.dinic(s, t)
+	return r.flows(), maxFlow
+}
+
+// dinic saturates the residual graph with flow from s to t
+// and returns the amount of flow sent.
+func (r *residualGraph[K, W]) dinic(s, t int) W {
+	level := make([]int, len(r.vertices))
+	next := make([]int, len(r.vertices)) // Next arc to try for each vertex
+
+	// buildLevels labels every vertex with its distance from the source
+	// in the residual graph and reports whether the sink was reached.
+	buildLevels := func() bool {
+		for i := range level {
+			level[i] = -1
+		}
+		level[s] = 0
+
+		Q := NewQueueOf[K](len(r.vertices))
+		Q.Push(r.vertices[s])
+		for Q.Len() > 0 {
+			u := r.index[Q.Pop()]
+			for _, arc := range r.adj[u] {
+				if arc.capacity > 0 && level[arc.to] == -1 {
+					level[arc.to] = level[u] + 1
+					Q.Push(r.vertices[arc.to])
+				}
+			}
+		}
+
+		return level[t] != -1
+	}
+
+	// augment pushes at most limit units of flow from u towards
+	// the sink along the level graph and returns the amount sent.
+	var augment func(u int, limit W) W
+	augment = func(u int, limit W) W {
+		if u == t {
+			return limit
+		}
+		for ; next[u] < len(r.adj[u]); next[u]++ {
+			arc := r.adj[u][next[u]]
+			if arc.capacity <= 0 || level[arc.to] != level[u]+1 {
+				continue
+			}
+			capacity := arc.capacity
+			if limit < capacity {
+				capacity = limit
+			}
+			if sent := augment(arc.to, capacity); sent > 0 {
+				r.push(u, next[u], sent)
+				return sent
+			}
+		}
+		return 0
+	}
+
+	// No path can carry more flow than the arc it leaves the source
+	// by, so the largest of those limits every augmenting path. Their
+	// sum could overflow W.
+	var limit W
+	for _, arc := range r.adj[s] {
+		if arc.capacity > limit {
+			limit = arc.capacity
+		}
+	}
+
+	var maxFlow W
+	for buildLevels() {
+		for i := range next {
+			next[i] = 0
+		}
+		for {
+			sent := augment(s, limit)
+			if sent == 0 {
+				break
+			}
+			maxFlow += sent
+		}
+	}
+
+	return maxFlow
+}
@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var maxFlowAlgorithms = map[string]func(*DirectedGraph, Vertex, Vertex) (map[Edge]int64, int64){
+	"EdmondsKarp": (*DirectedGraph).EdmondsKarp,
+	"Dinic":       (*DirectedGraph).Dinic,
+}
+
+// capacityGraph returns a graph of the given "start end capacity"
+// edges, with an optional cost as fourth value.
+func capacityGraph(edges ...string) *DirectedGraph {
+	g := &DirectedGraph{}
+	for i, e := range edges {
+		fields := strings.Fields(e)
+		edge := Edge{Start: Vertex{ID: fields[0]}, End: Vertex{ID: fields[1]}, ID: "e" + strconv.Itoa(i)}
+		edge.Capacity, _ = strconv.ParseInt(fields[2], 10, 64)
+		if len(fields) > 3 {
+			edge.Cost, _ = strconv.ParseInt(fields[3], 10, 64)
+		}
+		g.AddEdge(edge)
+	}
+	return g
+}
+
+// checkFlow checks that flow respects the capacities of g, is
+// conserved at every vertex but source and sink, and sends value
+// units from source to sink.
+func checkFlow(t *testing.T, g *DirectedGraph, flow map[Edge]int64, source, sink Vertex, value int64) {
+	t.Helper()
+	balance := make(map[Vertex]int64)
+	for e, f := range flow {
+		if f < 0 || f > e.Capacity {
+			t.Errorf("flow %d along %s exceeds its capacity %d", f, e.ID, e.Capacity)
+		}
+		balance[e.Start] -= f
+		balance[e.End] += f
+	}
+	for _, v := range g.Vertices() {
+		want := int64(0)
+		switch v {
+		case source:
+			want = -value
+		case sink:
+			want = value
+		}
+		if balance[v] != want {
+			t.Errorf("net flow into %s is %d, want %d", v.ID, balance[v], want)
+		}
+	}
+}
+
+func TestMaxFlow(t *testing.T) {
+	maxCapacity := strconv.FormatInt(math.MaxInt64, 10)
+	tests := []struct {
+		name         string
+		g            *DirectedGraph
+		source, sink string
+		value        int64
+	}{
+		{"benchmark6", loadTestGraph(t, "benchmark6.csv"), "source", "sink", 12},
+		{"single edge", capacityGraph("s t 5"), "s", "t", 5},
+		{"parallel edges", capacityGraph("s t 5", "s t 3"), "s", "t", 8},
+		{"bottleneck", capacityGraph("s a 10", "a b 1", "b t 10"), "s", "t", 1},
+		{"needs a backward arc", capacityGraph("s a 1", "s b 1", "a b 1", "a t 1", "b t 1"), "s", "t", 2},
+		{"disconnected", capacityGraph("s a 1", "b t 1"), "s", "t", 0},
+		{"wrong direction", capacityGraph("t s 1"), "s", "t", 0},
+		{"source is sink", capacityGraph("s t 1"), "s", "s", 0},
+		{"missing sink", capacityGraph("s t 1"), "s", "x", 0},
+		{"largest capacities", capacityGraph("s a "+maxCapacity, "s b "+maxCapacity, "a t 5", "b t 7"), "s", "t", 12},
+	}
+
+	for _, test := range tests {
+		source, sink := Vertex{ID: test.source}, Vertex{ID: test.sink}
+		for name, algorithm := range maxFlowAlgorithms {
+			flow, value := algorithm(test.g, source, sink)
+			if value != test.value {
+				t.Errorf("%s: %s = %d, want %d", test.name, name, value, test.value)
+			}
+			if value > 0 {
+				checkFlow(t, test.g, flow, source, sink, value)
+			}
+		}
+	}
+}
+
+// flowGrid returns gridGraph(n) with the weights as capacities.
+func flowGrid(n int) *DirectedGraph {
+	g := &DirectedGraph{}
+	for _, e := range gridGraph(n).Edges() {
+		e.Capacity = e.Weight
+		g.AddEdge(e)
+	}
+	return g
+}
+
+func TestMaxFlowAlgorithmsAgree(t *testing.T) {
+	g := flowGrid(12)
+	source, sink := Vertex{ID: "0"}, Vertex{ID: strconv.Itoa(12*12 - 1)}
+	edmondsKarpFlow, edmondsKarp := g.EdmondsKarp(source, sink)
+	dinicFlow, dinic := g.Dinic(source, sink)
+	if edmondsKarp != dinic {
+		t.Errorf("EdmondsKarp = %d, Dinic = %d", edmondsKarp, dinic)
+	}
+	checkFlow(t, g, edmondsKarpFlow, source, sink, edmondsKarp)
+	checkFlow(t, g, dinicFlow, source, sink, dinic)
+}
+
+func BenchmarkMaxFlow(b *testing.B) {
+	g := flowGrid(40)
+	source, sink := Vertex{ID: "0"}, Vertex{ID: strconv.Itoa(40*40 - 1)}
+	for name, algorithm := range maxFlowAlgorithms {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				algorithm(g, source, sink)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package graph
+
+import "errors"
+
+// ErrInsufficientCapacity is returned by MinCostFlow when the
+// requested demand can not be sent from the source to the sink.
+var ErrInsufficientCapacity = errors.New("graph: insufficient capacity to meet demand")
+
+// MinCostFlowResultOf holds the flow found by MinCostFlow.
+type MinCostFlowResultOf[K comparable, W Number] struct {
+	Flow  map[EdgeOf[K, W]]W // [Edge]Flow sent along the edge
+	Value W                  // Total flow from source to sink
+	Cost  W                  // Total cost of the flow
+}
+
+// MinCostFlowResult is the MinCostFlowResultOf a graph
+// with string vertex IDs and int64 weights.
+type MinCostFlowResult = MinCostFlowResultOf[string, int64]
+
+// MinCostFlow finds the cheapest way of sending demand units of flow
+// from source to sink, where every edge can carry at most its Capacity
+// and sending one unit along it costs its Cost. If demand is negative,
+// as much flow as possible is sent, i.e. it finds a minimum cost
+// maximum flow.
+//
+// It uses successive shortest paths: flow is repeatedly augmented along
+// the cheapest path in the residual graph, found with Dijkstra's
+// algorithm on costs made non-negative by vertex potentials. Negative
+// costs are allowed as long as there is no cycle of negative cost,
+// otherwise a *NegativeCycleError is returned.
+//
+// If the demand can not be met, the flow found is returned together
+// with ErrInsufficientCapacity.
+func (d *DirectedGraphOf[K, W]) MinCostFlow(source, sink VertexOf[K], demand W) (*MinCostFlowResultOf[K, W], error) {
+	if !d.HasVertex(source) || !d.HasVertex(sink) {
+		return nil, ErrVertexNotFound
+	}
+
+	// Initial potentials, only edges with capacity are part of the residual graph.
+	potentials, err := d.potentials(func(e EdgeOf[K, W]) (W, bool) { return e.Cost, e.Capacity > 0 })
+	if err != nil {
+		return nil, err
+	}
+
+	r := d.newResidualGraph()
+	h := make([]W, len(r.vertices))
+	for i, v := range r.vertices {
+		h[i] = potentials[v]
+	}
+
+	result := &MinCostFlowResultOf[K, W]{}
+	s, t, ok := r.terminals(source, sink)
+	for ok && (demand < 0 || result.Value < demand) {
+		dist, parent, reached := r.cheapestPaths(s, h)
+		if !reached[t] {
+			break // No path with free capacity left.
+		}
+
+		// Update the potentials so that the reduced costs stay
+		// non-negative, also for the arcs reversed by augmenting.
+		var farthest W
+		for v := range dist {
+			if reached[v] && dist[v] > farthest {
+				farthest = dist[v]
+			}
+		}
+		for v := range h {
+			if reached[v] {
+				h[v] += dist[v]
+			} else {
+				h[v] += farthest
+			}
+		}
+
+		// Find the bottleneck of the path...
+		bottleneck := r.adj[parent[t].from][parent[t].arc].capacity
+		if demand >= 0 && demand-result.Value < bottleneck {
+			bottleneck = demand - result.Value
+		}
+		for v := t; v != s; v = parent[v].from {
+			if c := r.adj[parent[v].from][parent[v].arc].capacity; c < bottleneck {
+				bottleneck = c
+			}
+		}
+		// ...and send that much flow along it.
+		for v := t; v != s; v = parent[v].from {
+			result.Cost += bottleneck * r.adj[parent[v].from][parent[v].arc].cost
+			r.push(parent[v].from, parent[v].arc, bottleneck)
+		}
+		result.Value += bottleneck
+	}
+
+	result.Flow = r.flows()
+	if demand >= 0 && result.Value < demand {
+		return result, ErrInsufficientCapacity
+	}
+
+	return result, nil
+}
+
+// cheapestPaths runs Dijkstra's algorithm from s over the arcs with
+// free capacity, using the costs reduced by the potentials h, which
+// must make them non-negative. It returns the reduced distance to
+// and the arc leading to every vertex, and which vertices were reached.
+func (r *residualGraph[K, W]) cheapestPaths(s int, h []W) ([]W, []residualStep, []bool) {
+	dist := make([]W, len(r.vertices))
+	parent := make([]residualStep, len(r.vertices))
+	reached := make([]bool, len(r.vertices))
+	done := make([]bool, len(r.vertices))
+	reached[s] = true
+
+	Q := NewVertexHeapOf[K, W](len(r.vertices))
+	Q.Push(r.vertices[s], 0)
+
+	for Q.Len() > 0 {
+		vertex, d := Q.Pop()
+		u := r.index[vertex]
+		done[u] = true
+
+		for i, arc := range r.adj[u] {
+			if arc.capacity <= 0 || done[arc.to] {
+				continue
+			}
+			alt := d + arc.cost + h[u] - h[arc.to]
+			if !reached[arc.to] || alt < dist[arc.to] {
+				reached[arc.to] = true
+				dist[arc.to] = alt
+				parent[arc.to] = residualStep{u, i}
+				Q.Push(r.vertices[arc.to], alt)
+			}
+		}
+	}
+
+	return dist, parent, reached
+}
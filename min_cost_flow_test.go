@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMinCostFlow(t *testing.T) {
+	twoRoutes := capacityGraph("s a 2 1", "a t 2 1", "s b 2 2", "b t 2 2")
+	negative := capacityGraph("s a 1 5", "s b 1 1", "b a 1 -3", "a t 2 0")
+
+	tests := []struct {
+		name         string
+		g            *DirectedGraph
+		source, sink string
+		demand       int64
+		value, cost  int64
+		err          error
+	}{
+		{"cheap route first", twoRoutes, "s", "t", 2, 2, 4, nil},
+		{"both routes", twoRoutes, "s", "t", 3, 3, 8, nil},
+		{"maximum flow", twoRoutes, "s", "t", -1, 4, 12, nil},
+		{"no demand", twoRoutes, "s", "t", 0, 0, 0, nil},
+		{"insufficient capacity", twoRoutes, "s", "t", 5, 4, 12, ErrInsufficientCapacity},
+		{"negative cost", negative, "s", "t", 1, 1, -2, nil},
+		{"negative cost and more", negative, "s", "t", 2, 2, 3, nil},
+		{"benchmark6", loadTestGraph(t, "benchmark6.csv"), "source", "sink", -1, 12, 0, nil},
+		{"missing sink", twoRoutes, "s", "x", 1, 0, 0, ErrVertexNotFound},
+	}
+
+	for _, test := range tests {
+		source, sink := Vertex{ID: test.source}, Vertex{ID: test.sink}
+		result, err := test.g.MinCostFlow(source, sink, test.demand)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: MinCostFlow returned %v, want %v", test.name, err, test.err)
+			continue
+		}
+		if result == nil {
+			continue
+		}
+		if result.Value != test.value || result.Cost != test.cost {
+			t.Errorf("%s: MinCostFlow sent %d at cost %d, want %d at cost %d", test.name, result.Value, result.Cost, test.value, test.cost)
+		}
+
+		checkFlow(t, test.g, result.Flow, source, sink, result.Value)
+		var cost int64
+		for e, f := range result.Flow {
+			cost += f * e.Cost
+		}
+		if cost != result.Cost {
+			t.Errorf("%s: the flow costs %d, but Cost is %d", test.name, cost, result.Cost)
+		}
+	}
+}
+
+func TestMinCostFlowNegativeCycle(t *testing.T) {
+	g := capacityGraph("s a 1 1", "a b 1 -2", "b a 1 1", "a t 1 1")
+	var cycleErr *NegativeCycleError
+	if _, err := g.MinCostFlow(Vertex{ID: "s"}, Vertex{ID: "t"}, 1); !errors.As(err, &cycleErr) {
+		t.Errorf("MinCostFlow returned %v, want a negative cycle", err)
+	}
+}
+
+// TestMinCostFlowMatchesMaxFlow checks that a minimum cost maximum
+// flow is a maximum flow.
+func TestMinCostFlowMatchesMaxFlow(t *testing.T) {
+	g := flowGrid(10)
+	for _, e := range g.Edges() {
+		g.RemoveEdge(e)
+		e.Cost = (e.Weight * 3) % 7
+		g.AddEdge(e)
+	}
+	source, sink := Vertex{ID: "0"}, Vertex{ID: "99"}
+
+	result, err := g.MinCostFlow(source, sink, -1)
+	if err != nil {
+		t.Fatalf("MinCostFlow failed: %s", err)
+	}
+	if _, maxFlow := g.Dinic(source, sink); result.Value != maxFlow {
+		t.Errorf("MinCostFlow sent %d, want the max flow %d", result.Value, maxFlow)
+	}
+	checkFlow(t, g, result.Flow, source, sink, result.Value)
+}
+
+func BenchmarkMinCostFlow(b *testing.B) {
+	g := flowGrid(20)
+	source, sink := Vertex{ID: "0"}, Vertex{ID: "399"}
+	for i := 0; i < b.N; i++ {
+		if _, err := g.MinCostFlow(source, sink, -1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
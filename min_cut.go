@@ -0,0 +1,49 @@
+package graph
+
+// CutOf is a partition of the vertices of a graph into a source side and
+// a sink side. Edges holds every edge leading from the source side to
+// the sink side together with its capacity, and Capacity their sum.
+type CutOf[K comparable, W Number] struct {
+	SourceSide []VertexOf[K]
+	Edges      map[EdgeOf[K, W]]W // [Edge]Capacity
+	Capacity   W
+}
+
+// Cut is a cut of a graph with string vertex IDs and int64 capacities.
+type Cut = CutOf[string, int64]
+
+// MinCut finds a minimum s-t cut separating source from sink, i.e. the
+// set of edges with the smallest total capacity whose removal leaves no
+// path from source to sink. By the max-flow min-cut theorem its capacity
+// equals the maximum flow, and the source side is the set of vertices
+// still reachable from source in the residual graph once the maximum
+// flow has been found.
+func (d *DirectedGraphOf[K, W]) MinCut(source, sink VertexOf[K]) *CutOf[K, W] {
+	cut := &CutOf[K, W]{Edges: make(map[EdgeOf[K, W]]W)}
+
+	r := d.newResidualGraph()
+	s, t, ok := r.terminals(source, sink)
+	if !ok {
+		if d.HasVertex(source) {
+			cut.SourceSide = []VertexOf[K]{source}
+		}
+		return cut
+	}
+	r.dinic(s, t)
+
+	sourceSide := r.reachable(s)
+	for i, v := range r.vertices {
+		if sourceSide[i] {
+			cut.SourceSide = append(cut.SourceSide, v)
+		}
+	}
+
+	for i, edge := range r.edges {
+		if sourceSide[r.index[edge.Start]] && !sourceSide[r.index[edge.End]] {
+			cut.Edges[edge] = r.capacity[i]
+			cut.Capacity += r.capacity[i]
+		}
+	}
+
+	return cut
+}
@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestMinCut(t *testing.T) {
+	maxCapacity := strconv.FormatInt(math.MaxInt64, 10)
+	tests := []struct {
+		name         string
+		g            *DirectedGraph
+		source, sink string
+		capacity     int64
+		edges        []string // IDs of the cut edges
+	}{
+		{"benchmark6", loadTestGraph(t, "benchmark6.csv"), "source", "sink", 12, nil},
+		{"bottleneck", capacityGraph("s a 10", "a b 1", "b t 10"), "s", "t", 1, []string{"e1"}},
+		{"two edges", capacityGraph("s a 2", "s b 3", "a t 5", "b t 5"), "s", "t", 5, []string{"e0", "e1"}},
+		{"disconnected", capacityGraph("s a 1", "b t 1"), "s", "t", 0, []string{}},
+		{"source is sink", capacityGraph("s t 1"), "s", "s", 0, []string{}},
+		{"largest capacities", capacityGraph("s a "+maxCapacity, "s b "+maxCapacity, "a t 5", "b t 7"), "s", "t", 12, []string{"e2", "e3"}},
+	}
+
+	for _, test := range tests {
+		source, sink := Vertex{ID: test.source}, Vertex{ID: test.sink}
+		cut := test.g.MinCut(source, sink)
+		if cut.Capacity != test.capacity {
+			t.Errorf("%s: cut capacity = %d, want %d", test.name, cut.Capacity, test.capacity)
+		}
+		if _, maxFlow := test.g.Dinic(source, sink); cut.Capacity != maxFlow {
+			t.Errorf("%s: cut capacity %d differs from the max flow %d", test.name, cut.Capacity, maxFlow)
+		}
+		if test.edges != nil {
+			if len(cut.Edges) != len(test.edges) {
+				t.Errorf("%s: cut has edges %v, want %v", test.name, cut.Edges, test.edges)
+			}
+			for _, id := range test.edges {
+				if e, _ := test.g.EdgeByID(id); cut.Edges[e] != e.Capacity {
+					t.Errorf("%s: cut has edges %v, want %v", test.name, cut.Edges, test.edges)
+				}
+			}
+		}
+
+		// The cut must separate source from sink
+		sourceSide := make(map[Vertex]bool)
+		for _, v := range cut.SourceSide {
+			sourceSide[v] = true
+		}
+		if source == sink {
+			continue // Nothing to separate
+		}
+		if !sourceSide[source] || sourceSide[sink] {
+			t.Errorf("%s: source side %v does not separate %s from %s", test.name, cut.SourceSide, test.source, test.sink)
+		}
+		var total int64
+		for _, e := range test.g.Edges() {
+			if sourceSide[e.Start] && !sourceSide[e.End] {
+				total += e.Capacity
+			}
+		}
+		if total != cut.Capacity {
+			t.Errorf("%s: edges leaving the source side add up to %d, want %d", test.name, total, cut.Capacity)
+		}
+	}
+}
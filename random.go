@@ -0,0 +1,23 @@
+package graph
+
+import "math/rand"
+
+// RandomOptions controls the randomness of the randomized algorithms,
+// such as GreedyMatching. Runs with the same options on the same graph
+// give the same result.
+type RandomOptions struct {
+	// Rand is the source of randomness. If nil, a new one is
+	// seeded with Seed. A Rand must not be shared between
+	// goroutines, and is advanced by every run it is used for.
+	Rand *rand.Rand
+	// Seed seeds the source used when Rand is nil.
+	Seed int64
+}
+
+// rand returns the source of randomness to use.
+func (o RandomOptions) rand() *rand.Rand {
+	if o.Rand != nil {
+		return o.Rand
+	}
+	return rand.New(rand.NewSource(o.Seed))
+}
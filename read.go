@@ -0,0 +1,291 @@
+package graph
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Format is a file format graphs can be read from.
+type Format string
+
+// The formats understood by the loaders.
+const (
+	// FormatCSV is a table of edges, one per row, tab separated unless
+	// LoadOptions.Separator says otherwise. See ReadDirectedGraph.
+	FormatCSV Format = "csv"
+	// FormatGraphML is GraphML, as written by e.g. Gephi and NetworkX.
+	// The data of nodes and edges become attributes, typed by their key
+	// declarations, except the weight, capacity and cost of edges.
+	FormatGraphML Format = "graphml"
+	// FormatGML is the Graph Modelling Language. Nodes are identified by
+	// their id, and their other values become attributes. Edges read
+	// their weight, capacity, cost and id, with the rest as attributes.
+	FormatGML Format = "gml"
+	// FormatDOT is the Graphviz DOT language. Node and edge attributes,
+	// including defaults, are kept, and subgraphs are flattened. DOT
+	// values are untyped, so they are typed by ParseAttribute, and a
+	// string like "007" reads back as a number.
+	FormatDOT Format = "dot"
+	// FormatMatrixMarket is a Matrix Market coordinate matrix, with an
+	// edge of the entry's value as weight for every entry i, j in the
+	// file, explicit zeros included. The vertices are numbered from 1
+	// to the number of rows or columns. When reading into integer
+	// weights, entries of real matrices must be whole numbers. Others
+	// are skipped, or fail the read in strict mode.
+	FormatMatrixMarket Format = "matrixmarket"
+	// FormatEdgeList is a plain list of edges as used by SNAP, one
+	// "source target" or "source target weight" per line, separated
+	// by whitespace. Lines starting with # or % are comments.
+	FormatEdgeList Format = "edgelist"
+	// FormatJSON is the node-link JSON written by WriteJSON. It is
+	// recognized so such files are not mistaken for CSV, but the
+	// loaders can not read it.
+	FormatJSON Format = "json"
+)
+
+// formatExtensions maps file extensions to formats.
+var formatExtensions = map[string]Format{
+	".csv":      FormatCSV,
+	".tsv":      FormatCSV,
+	".graphml":  FormatGraphML,
+	".gml":      FormatGML,
+	".dot":      FormatDOT,
+	".gv":       FormatDOT,
+	".mtx":      FormatMatrixMarket,
+	".edges":    FormatEdgeList,
+	".edgelist": FormatEdgeList,
+	".json":     FormatJSON,
+}
+
+// FormatFromPath returns the format of the file at filePath going by
+// its extension, ignoring any .gz suffix as the loaders decompress
+// such files. It reports false if the extension is not known.
+func FormatFromPath(filePath string) (Format, bool) {
+	filePath = strings.TrimSuffix(strings.ToLower(filePath), ".gz")
+	format, ok := formatExtensions[filepath.Ext(filePath)]
+	return format, ok
+}
+
+// LoadOptions controls how the loaders, such as ReadDirectedGraph
+// and LoadDirectedGraph, read their input.
+type LoadOptions struct {
+	// Format is the format of the input. ReadDirectedGraph and
+	// ReadUndirectedGraph default to CSV, while LoadDirectedGraph and
+	// LoadUndirectedGraph go by the file extension, see FormatFromPath.
+	Format Format
+	// Separator is the CSV value separator, a tab if left at zero.
+	Separator rune
+	// Strict makes loading fail with a *ParseError on the first row
+	// (or element) which is not a valid edge. Otherwise such rows are
+	// skipped and returned as warnings.
+	Strict bool
+	// Columns maps CSV header names (in any case) to the field the column
+	// is read into, on top of the names understood by default such as
+	// "Vertex1", "Vertex2", "weight" and "id".
+	Columns map[string]string
+	// Fields gives the field of every column of a CSV file without a
+	// header row. Every row must then have exactly one value per field.
+	// If nil, rows are read as DefaultFields, or as WeightlessFields
+	// if they have one value less.
+	Fields []string
+	// Multigraph makes the loaders return a multigraph, in which edges
+	// with the ID of an earlier edge are dropped, see
+	// DirectedGraph.IsMultigraph. Otherwise parallel edges are kept
+	// unless identical.
+	Multigraph bool
+}
+
+// ParseError describes a part of the input, such as a row of a CSV
+// file, which could not be read.
+type ParseError struct {
+	File   string // Path of the file, if read from one
+	Line   int    // Line the row starts on counting from 1, or 0 if not known
+	Column int    // CSV column of the offending value counting from 1, or 0 for the whole row
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	var position string
+	switch {
+	case e.File != "" && e.Line > 0:
+		position = fmt.Sprintf("%s:%d", e.File, e.Line)
+	case e.File != "":
+		position = e.File
+	default:
+		position = fmt.Sprintf("line %d", e.Line)
+	}
+	if e.Column > 0 {
+		return fmt.Sprintf("graph: %s: column %d: %s", position, e.Column, e.Reason)
+	}
+	return fmt.Sprintf("graph: %s: %s", position, e.Reason)
+}
+
+// rowError returns a *ParseError for the given column of the
+// current row, the file and line are filled in by the reader.
+func rowError(column int, format string, a ...any) *ParseError {
+	return &ParseError{Column: column, Reason: fmt.Sprintf(format, a...)}
+}
+
+// graphBuilder is implemented by both kinds of graph with string
+// vertex IDs, the only kind of ID the formats have.
+type graphBuilder[W Number] interface {
+	Grow(vertices, edges int)
+	AddVertex(v Vertex)
+	AddEdge(e EdgeOf[string, W])
+	SetVertexAttribute(v Vertex, name string, value any) bool
+	SetEdgeAttribute(e EdgeOf[string, W], name string, value any) bool
+}
+
+// graphReader holds the state shared by the readers of all formats.
+// The weights, capacities and costs of the edges are read as W.
+type graphReader[W Number] struct {
+	g        graphBuilder[W]
+	name     string // File name used in the errors
+	opts     LoadOptions
+	warnings []*ParseError
+	edges    int // Number of IDs generated
+}
+
+// readGraph reads a graph in the format given by opts into g,
+// decompressing it first if it is gzip compressed. Parts
+// of the input which are not valid edges fail the whole read in strict
+// mode, and are otherwise skipped and returned as warnings.
+func readGraph[W Number](g graphBuilder[W], r io.Reader, name string, opts LoadOptions) ([]*ParseError, error) {
+	gr := &graphReader[W]{g: g, name: name, opts: opts}
+
+	// Look for the gzip magic number, whatever the format
+	buffered := bufio.NewReader(r)
+	r = buffered
+	if magic, _ := buffered.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		decompressed, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, err
+		}
+		defer decompressed.Close()
+		r = decompressed
+	}
+
+	var err error
+	switch opts.Format {
+	case "", FormatCSV:
+		err = gr.readCSV(r)
+	case FormatGraphML:
+		err = gr.readGraphML(r)
+	case FormatGML:
+		err = gr.readGML(r)
+	case FormatDOT:
+		err = gr.readDOT(r)
+	case FormatMatrixMarket:
+		err = gr.readMatrixMarket(r)
+	case FormatEdgeList:
+		err = gr.readEdgeList(r)
+	case FormatJSON:
+		err = fmt.Errorf("graph: unsupported input format %q", opts.Format)
+	default:
+		err = fmt.Errorf("graph: unknown format %q", opts.Format)
+	}
+	if err != nil {
+		if parseErr, ok := err.(*ParseError); ok {
+			parseErr.File = name
+		}
+		return nil, err
+	}
+
+	return gr.warnings, nil
+}
+
+// skip handles a part of the input which is not a valid edge.
+func (gr *graphReader[W]) skip(err *ParseError) error {
+	err.File = gr.name
+	if gr.opts.Strict {
+		return err
+	}
+	gr.warnings = append(gr.warnings, err)
+	return nil
+}
+
+// nextID returns an ID for an edge which has none in the input,
+// numbering them "e1", "e2" and so on in the order they are read.
+func (gr *graphReader[W]) nextID() string {
+	gr.edges++
+	return "e" + strconv.Itoa(gr.edges)
+}
+
+// setVertexAttributes sets the given attributes of v.
+func (gr *graphReader[W]) setVertexAttributes(v Vertex, attributes Attributes) {
+	for name, value := range attributes {
+		gr.g.SetVertexAttribute(v, name, value)
+	}
+}
+
+// setEdgeAttributes sets the given attributes of e.
+func (gr *graphReader[W]) setEdgeAttributes(e EdgeOf[string, W], attributes Attributes) {
+	for name, value := range attributes {
+		gr.g.SetEdgeAttribute(e, name, value)
+	}
+}
+
+// isFloat reports whether W is a floating point type.
+func isFloat[W Number]() bool {
+	var half W = 1
+	half /= 2
+	return half != 0
+}
+
+// parseNumber parses the value of a numeric Edge field as a W. Formats
+// without integer types write them as floats, so for integer types
+// those are accepted as long as they are whole numbers.
+func parseNumber[W Number](s string) (W, error) {
+	s = strings.TrimSpace(s)
+	i, err := strconv.ParseInt(s, 0, 64)
+	if err != nil {
+		f, floatErr := strconv.ParseFloat(s, 64)
+		switch {
+		case floatErr == nil && isFloat[W]():
+			return W(f), nil
+		case isFloat[W]():
+			return 0, fmt.Errorf("%q is not a number", s)
+		case floatErr != nil || f != math.Trunc(f) || math.Abs(f) > math.MaxInt64:
+			return 0, fmt.Errorf("%q is not an integer", s)
+		}
+		i = int64(f)
+	}
+	if !isFloat[W]() && int64(W(i)) != i {
+		return 0, fmt.Errorf("%q is out of range", s)
+	}
+	return W(i), nil
+}
+
+// formatNumber formats the value of a numeric Edge field so that
+// parseNumber reads it back.
+func formatNumber[W Number](n W) string {
+	if isFloat[W]() {
+		return strconv.FormatFloat(float64(n), 'g', -1, 64)
+	}
+	return strconv.FormatInt(int64(n), 10)
+}
+
+// takeEdgeFields moves the weight, capacity and cost found among
+// the attributes of an edge into the matching fields of e. It fails
+// if one of them is not a valid W.
+func takeEdgeFields[W Number](e *EdgeOf[string, W], attributes Attributes) error {
+	for _, name := range []string{WeightAttribute, CapacityAttribute, CostAttribute} {
+		value, ok := attributes[name]
+		if !ok {
+			continue
+		}
+		n, err := parseNumber[W](FormatAttribute(value))
+		if err != nil {
+			return fmt.Errorf("invalid %s: %s", name, err)
+		}
+		e.SetAttribute(name, n)
+		delete(attributes, name)
+	}
+	return nil
+}
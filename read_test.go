@@ -0,0 +1,202 @@
+package graph
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadMatchesLoad(t *testing.T) {
+	for _, name := range []string{"benchmark1.csv", "benchmark4.csv", "benchmark6.csv"} {
+		path := filepath.Join("csv_files", name)
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		read, _, err := ReadDirectedGraph(file, LoadOptions{})
+		file.Close()
+		if err != nil {
+			t.Fatalf("reading %s failed: %s", name, err)
+		}
+		loaded := loadTestGraph(t, name)
+
+		if fmt.Sprint(read.Edges()) != fmt.Sprint(loaded.Edges()) {
+			t.Errorf("%s: read %v, loaded %v", name, read.Edges(), loaded.Edges())
+		}
+	}
+}
+
+// failingReader returns err once it has returned data.
+type failingReader struct {
+	data string
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestReadError(t *testing.T) {
+	broken := errors.New("broken pipe")
+	r := &failingReader{data: "a\tb\t1\te1\n", err: broken}
+	if _, _, err := ReadUndirectedGraph(r, LoadOptions{}); !errors.Is(err, broken) {
+		t.Errorf("ReadUndirectedGraph returned %v, want %v", err, broken)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	_, _, err := LoadDirectedGraph(filepath.Join(t.TempDir(), "missing.csv"), LoadOptions{})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LoadDirectedGraph returned %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+// readTest reads input in the given format into a DirectedGraph.
+func readTest(t *testing.T, input string, opts LoadOptions) (*DirectedGraph, []*ParseError) {
+	t.Helper()
+	g, warnings, err := ReadDirectedGraph(strings.NewReader(input), opts)
+	if err != nil {
+		t.Fatalf("reading %s failed: %s\n%s", opts.Format, err, input)
+	}
+	return g, warnings
+}
+
+// edgeSummary lists the edges of g as "start>end:weight:id".
+func edgeSummary(g Graph) string {
+	var edges []string
+	for _, e := range g.Edges() {
+		edges = append(edges, fmt.Sprintf("%s>%s:%d:%s", e.Start.ID, e.End.ID, e.Weight, e.ID))
+	}
+	return strings.Join(edges, " ")
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := []struct {
+		path   string
+		format Format
+		ok     bool
+	}{
+		{"edges.csv", FormatCSV, true},
+		{"EDGES.TSV", FormatCSV, true},
+		{"dir.d/graph.graphml", FormatGraphML, true},
+		{"graph.gml", FormatGML, true},
+		{"graph.gv", FormatDOT, true},
+		{"matrix.mtx.gz", FormatMatrixMarket, true},
+		{"edges.edgelist.gz", FormatEdgeList, true},
+		// .txt files are read as CSV, as they always were
+		{"roadNet-CA.txt.gz", "", false},
+		{"graph.json", FormatJSON, true},
+		{"graph.xyz", "", false},
+		{"graph", "", false},
+	}
+
+	for _, test := range tests {
+		if format, ok := FormatFromPath(test.path); format != test.format || ok != test.ok {
+			t.Errorf("FormatFromPath(%q) = %q, %t, want %q, %t", test.path, format, ok, test.format, test.ok)
+		}
+	}
+}
+
+func TestReadGzip(t *testing.T) {
+	tests := []struct {
+		format Format
+		input  string
+	}{
+		{FormatCSV, "a\tb\t1\te1\n"},
+		{FormatEdgeList, "# comment\na b 1\n"},
+		{FormatGML, "graph [ edge [ source \"a\" target \"b\" weight 1 id \"e1\" ] ]"},
+	}
+
+	for _, test := range tests {
+		var compressed bytes.Buffer
+		w := gzip.NewWriter(&compressed)
+		w.Write([]byte(test.input))
+		w.Close()
+
+		g, _ := readTest(t, compressed.String(), LoadOptions{Format: test.format, Strict: true})
+		if got := edgeSummary(g); got != "a>b:1:e1" {
+			t.Errorf("%s: read %s from gzip compressed input, want a>b:1:e1", test.format, got)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "edges.csv.gz")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := gzip.NewWriter(file)
+	w.Write([]byte("a\tb\t1\te1\n"))
+	w.Close()
+	file.Close()
+	if g, _, err := LoadUndirectedGraph(path, LoadOptions{Strict: true}); err != nil || g.EdgeCount() != 1 {
+		t.Errorf("LoadUndirectedGraph(%s) returned %v, %v", path, g, err)
+	}
+}
+
+func TestReadUnsupportedFormat(t *testing.T) {
+	for _, format := range []Format{FormatJSON, "yaml"} {
+		if _, _, err := ReadDirectedGraph(strings.NewReader("{}"), LoadOptions{Format: format}); err == nil {
+			t.Errorf("reading %s succeeded", format)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "graph.json")
+	if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := LoadDirectedGraph(path, LoadOptions{}); err == nil || !strings.Contains(err.Error(), "unsupported input format") {
+		t.Errorf("LoadDirectedGraph(%s) returned %v, want an unsupported input format error", path, err)
+	}
+}
+
+func TestReadWriteFloatWeights(t *testing.T) {
+	g := &DirectedGraphOf[string, float64]{}
+	a, b, c := Vertex{ID: "a"}, Vertex{ID: "b"}, Vertex{ID: "c"}
+	g.AddEdge(EdgeOf[string, float64]{ID: "e1", Start: a, End: b, Weight: 0.5, Capacity: 1.25})
+	g.AddEdge(EdgeOf[string, float64]{ID: "e2", Start: b, End: c, Weight: -2, Cost: 1e-3})
+
+	writers := map[Format]func(io.Writer, GraphOf[string, float64]) error{
+		FormatCSV:     WriteCSVOf[float64],
+		FormatDOT:     WriteDOTOf[float64],
+		FormatGraphML: WriteGraphMLOf[float64],
+	}
+	for format, write := range writers {
+		var buf bytes.Buffer
+		if err := write(&buf, g); err != nil {
+			t.Fatalf("writing %s failed: %s", format, err)
+		}
+		read, warnings, err := ReadDirectedGraphOf[float64](&buf, LoadOptions{Format: format})
+		if err != nil || len(warnings) != 0 {
+			t.Fatalf("reading %s returned %v and %v", format, warnings, err)
+		}
+		if fmt.Sprint(read.Edges()) != fmt.Sprint(g.Edges()) {
+			t.Errorf("%s: read %v, want %v", format, read.Edges(), g.Edges())
+		}
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	if n, err := parseNumber[int8]("127"); n != 127 || err != nil {
+		t.Errorf("parseNumber[int8](127) = %d, %v", n, err)
+	}
+	if _, err := parseNumber[int8]("128"); err == nil {
+		t.Error("parseNumber[int8](128) did not fail")
+	}
+	if _, err := parseNumber[int64]("0.5"); err == nil {
+		t.Error("parseNumber[int64](0.5) did not fail")
+	}
+	if n, err := parseNumber[float32]("0.5"); n != 0.5 || err != nil {
+		t.Errorf("parseNumber[float32](0.5) = %v, %v", n, err)
+	}
+}
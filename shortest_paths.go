@@ -0,0 +1,306 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrVertexNotFound is returned when an algorithm is
+	// given a vertex which is not part of the graph.
+	ErrVertexNotFound = errors.New("graph: vertex not found")
+	// ErrNegativeWeight is returned by Dijkstra based algorithms
+	// when the graph contains an edge with a negative weight. Use
+	// BellmanFord or SPFA for such graphs instead.
+	ErrNegativeWeight = errors.New("graph: negative edge weight")
+)
+
+// NegativeCycleErrorOf is returned when a cycle with a negative total
+// weight can be reached from the source, in which case no shortest
+// paths exist. Cycle holds the edges of the cycle in order.
+type NegativeCycleErrorOf[K comparable, W Number] struct {
+	Cycle []EdgeOf[K, W]
+}
+
+// NegativeCycleError is the NegativeCycleErrorOf returned
+// for graphs with string vertex IDs and int64 weights.
+type NegativeCycleError = NegativeCycleErrorOf[string, int64]
+
+func (e *NegativeCycleErrorOf[K, W]) Error() string {
+	var ids []string
+	for _, edge := range e.Cycle {
+		ids = append(ids, fmt.Sprint(edge.Start.ID))
+	}
+	if len(e.Cycle) > 0 {
+		ids = append(ids, fmt.Sprint(e.Cycle[0].Start.ID))
+	}
+
+	return fmt.Sprintf("graph: negative cycle %s", strings.Join(ids, " -> "))
+}
+
+// ShortestPathTreeOf holds the result of a single-source shortest
+// path search. Distances and Predecessors only contain the vertices
+// which can be reached from Source.
+type ShortestPathTreeOf[K comparable, W Number] struct {
+	Source       VertexOf[K]
+	Distances    map[VertexOf[K]]W            // [Vertex]Distance from Source
+	Predecessors map[VertexOf[K]]EdgeOf[K, W] // [Vertex]Last edge on the shortest path
+}
+
+// ShortestPathTree is the ShortestPathTreeOf a graph
+// with string vertex IDs and int64 weights.
+type ShortestPathTree = ShortestPathTreeOf[string, int64]
+
+// Reachable reports whether there is a path from the tree's source to v.
+func (t *ShortestPathTreeOf[K, W]) Reachable(v VertexOf[K]) bool {
+	_, ok := t.Distances[v]
+	return ok
+}
+
+// DistanceTo returns the length of the shortest path to v, and
+// false if v can not be reached from the source.
+func (t *ShortestPathTreeOf[K, W]) DistanceTo(v VertexOf[K]) (W, bool) {
+	dist, ok := t.Distances[v]
+	return dist, ok
+}
+
+// PathTo returns the edges on the shortest path from the source
+// to target, in order. It returns nil if target can not be reached,
+// and an empty path if target is the source itself.
+func (t *ShortestPathTreeOf[K, W]) PathTo(target VertexOf[K]) []EdgeOf[K, W] {
+	if !t.Reachable(target) {
+		return nil
+	}
+
+	var reversePath []EdgeOf[K, W]
+	for v := target; v != t.Source; {
+		edge := t.Predecessors[v]
+		reversePath = append(reversePath, edge)
+		v = edge.Start
+	}
+
+	path := make([]EdgeOf[K, W], 0, len(reversePath))
+	for i := len(reversePath) - 1; i >= 0; i-- {
+		path = append(path, reversePath[i])
+	}
+
+	return path
+}
+
+// ShortestPaths uses Dijkstra's algorithm to find the shortest path
+// from source to every vertex reachable from it. The vertices are
+// kept in a VertexHeap, giving a running time of O((V+E) log V).
+// It returns ErrVertexNotFound if source is not part of the graph
+// and ErrNegativeWeight if any edge has a negative weight.
+func (d *DirectedGraphOf[K, W]) ShortestPaths(source VertexOf[K]) (*ShortestPathTreeOf[K, W], error) {
+	if !d.HasVertex(source) {
+		return nil, ErrVertexNotFound
+	}
+	for _, edges := range d.edges {
+		for _, edge := range edges {
+			if edge.Weight < 0 {
+				return nil, ErrNegativeWeight
+			}
+		}
+	}
+
+	return d.dijkstra(source, func(e EdgeOf[K, W]) W { return e.Weight }), nil
+}
+
+// dijkstra runs Dijkstra's algorithm from source using the
+// given (non-negative) weight function for the edges.
+func (d *DirectedGraphOf[K, W]) dijkstra(source VertexOf[K], weight func(EdgeOf[K, W]) W) *ShortestPathTreeOf[K, W] {
+	dists := map[VertexOf[K]]W{source: 0} // Dist to source is 0
+	previousOptimalPathEdge := make(map[VertexOf[K]]EdgeOf[K, W])
+	done := make(map[VertexOf[K]]bool)
+
+	Q := NewVertexHeapOf[K, W](len(d.vertices))
+	Q.Push(source, 0)
+
+	for Q.Len() > 0 {
+		// Take the vertex u with the smallest distance, its
+		// distance can no longer be improved.
+		u, dist := Q.Pop()
+		done[u] = true
+
+		for _, v := range d.edges[u] {
+			if done[v.End] {
+				continue
+			}
+			alt := dist + weight(v)
+			if old, ok := dists[v.End]; !ok || alt < old {
+				dists[v.End] = alt
+				previousOptimalPathEdge[v.End] = v
+				Q.Push(v.End, alt)
+			}
+		}
+	}
+
+	return &ShortestPathTreeOf[K, W]{
+		Source:       source,
+		Distances:    dists,
+		Predecessors: previousOptimalPathEdge,
+	}
+}
+
+// predecessorCycle follows the predecessor edges backwards from
+// start and returns the first cycle it runs into, in order. It
+// returns nil if the walk ends without finding a cycle.
+func predecessorCycle[K comparable, W Number](predecessors map[VertexOf[K]]EdgeOf[K, W], start VertexOf[K]) []EdgeOf[K, W] {
+	visited := make(map[VertexOf[K]]bool)
+	v := start
+	for !visited[v] {
+		visited[v] = true
+		edge, ok := predecessors[v]
+		if !ok {
+			return nil // Reached the source
+		}
+		v = edge.Start
+	}
+
+	// v is on the cycle, walk around it once more to collect it.
+	var reverseCycle []EdgeOf[K, W]
+	for u := v; ; {
+		edge := predecessors[u]
+		reverseCycle = append(reverseCycle, edge)
+		u = edge.Start
+		if u == v {
+			break
+		}
+	}
+
+	cycle := make([]EdgeOf[K, W], 0, len(reverseCycle))
+	for i := len(reverseCycle) - 1; i >= 0; i-- {
+		cycle = append(cycle, reverseCycle[i])
+	}
+
+	return cycle
+}
+
+// BellmanFord finds the shortest path from source to every vertex
+// reachable from it using the Bellman-Ford algorithm, which unlike
+// ShortestPaths handles negative edge weights. It runs in O(VE).
+// If a negative cycle can be reached from source, it returns a
+// *NegativeCycleError holding the cycle.
+func (d *DirectedGraphOf[K, W]) BellmanFord(source VertexOf[K]) (*ShortestPathTreeOf[K, W], error) {
+	if !d.HasVertex(source) {
+		return nil, ErrVertexNotFound
+	}
+
+	dists := map[VertexOf[K]]W{source: 0}
+	previousOptimalPathEdge, err := d.bellmanFord(dists, func(e EdgeOf[K, W]) (W, bool) { return e.Weight, true })
+	if err != nil {
+		return nil, err
+	}
+
+	return &ShortestPathTreeOf[K, W]{
+		Source:       source,
+		Distances:    dists,
+		Predecessors: previousOptimalPathEdge,
+	}, nil
+}
+
+// bellmanFord relaxes the edges of the graph until the distances
+// in dists (which must hold the starting vertices) can no longer be
+// improved, and returns the predecessor edge of every vertex reached.
+// The weight function gives the weight of each edge, and false for
+// edges which should be left out.
+func (d *DirectedGraphOf[K, W]) bellmanFord(dists map[VertexOf[K]]W, weight func(EdgeOf[K, W]) (W, bool)) (map[VertexOf[K]]EdgeOf[K, W], error) {
+	previousOptimalPathEdge := make(map[VertexOf[K]]EdgeOf[K, W])
+
+	// relax makes a single pass over all edges and returns
+	// the last edge that shortened a path, if any.
+	relax := func() (EdgeOf[K, W], bool) {
+		var relaxedEdge EdgeOf[K, W]
+		relaxed := false
+		for _, u := range d.vertices {
+			dist, ok := dists[u]
+			if !ok {
+				continue // Not reached (yet)
+			}
+			for _, edge := range d.edges[u] {
+				w, ok := weight(edge)
+				if !ok {
+					continue
+				}
+				alt := dist + w
+				if old, ok := dists[edge.End]; !ok || alt < old {
+					dists[edge.End] = alt
+					previousOptimalPathEdge[edge.End] = edge
+					relaxedEdge = edge
+					relaxed = true
+				}
+			}
+		}
+		return relaxedEdge, relaxed
+	}
+
+	// All shortest paths are found after at most V-1 passes
+	for i := 1; i < len(d.vertices); i++ {
+		if _, relaxed := relax(); !relaxed {
+			break
+		}
+	}
+
+	// If we can still shorten a path there is a negative cycle
+	if edge, relaxed := relax(); relaxed {
+		return nil, &NegativeCycleErrorOf[K, W]{Cycle: predecessorCycle(previousOptimalPathEdge, edge.End)}
+	}
+
+	return previousOptimalPathEdge, nil
+}
+
+// SPFA is the "Shortest Path Faster Algorithm" variant of BellmanFord.
+// Instead of relaxing every edge in each pass it only relaxes the edges
+// of vertices whose distance changed, which is usually much faster
+// in practice while keeping the same O(VE) worst case. Negative cycles
+// are reported the same way as by BellmanFord.
+func (d *DirectedGraphOf[K, W]) SPFA(source VertexOf[K]) (*ShortestPathTreeOf[K, W], error) {
+	if !d.HasVertex(source) {
+		return nil, ErrVertexNotFound
+	}
+
+	dists := map[VertexOf[K]]W{source: 0}
+	previousOptimalPathEdge := make(map[VertexOf[K]]EdgeOf[K, W])
+	pathLength := map[VertexOf[K]]int{source: 0} // Number of edges on the current path
+	inQueue := map[VertexOf[K]]bool{source: true}
+
+	Q := NewQueueOf[K](len(d.vertices))
+	Q.Push(source)
+
+	for Q.Len() > 0 {
+		u := Q.Pop()
+		inQueue[u] = false
+
+		for _, edge := range d.edges[u] {
+			alt := dists[u] + edge.Weight
+			if old, ok := dists[edge.End]; ok && alt >= old {
+				continue
+			}
+
+			dists[edge.End] = alt
+			previousOptimalPathEdge[edge.End] = edge
+			pathLength[edge.End] = pathLength[u] + 1
+
+			// A shortest path never has more than V-1 edges,
+			// so a longer one must go through a negative cycle.
+			if pathLength[edge.End] >= len(d.vertices) {
+				if cycle := predecessorCycle(previousOptimalPathEdge, edge.End); cycle != nil {
+					return nil, &NegativeCycleErrorOf[K, W]{Cycle: cycle}
+				}
+			}
+
+			if !inQueue[edge.End] {
+				Q.Push(edge.End)
+				inQueue[edge.End] = true
+			}
+		}
+	}
+
+	return &ShortestPathTreeOf[K, W]{
+		Source:       source,
+		Distances:    dists,
+		Predecessors: previousOptimalPathEdge,
+	}, nil
+}
@@ -0,0 +1,377 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// loadTestGraph loads one of the files in csv_files as a DirectedGraph.
+func loadTestGraph(tb testing.TB, name string) *DirectedGraph {
+	tb.Helper()
+	g, _, err := LoadDirectedGraph(filepath.Join("csv_files", name), LoadOptions{})
+	if err != nil {
+		tb.Fatalf("loading %s failed: %s", name, err)
+	}
+	return g
+}
+
+// gridGraph returns a directed n by n grid with edges to the right and
+// downwards, weighted by a fixed pattern so all results are repeatable.
+func gridGraph(n int) *DirectedGraph {
+	g := &DirectedGraph{}
+	vertex := func(row, column int) Vertex {
+		return Vertex{ID: strconv.Itoa(row*n + column)}
+	}
+	for row := 0; row < n; row++ {
+		for column := 0; column < n; column++ {
+			weight := int64((row*7+column*13)%10 + 1)
+			if column+1 < n {
+				g.AddEdge(Edge{Start: vertex(row, column), End: vertex(row, column+1), Weight: weight})
+			}
+			if row+1 < n {
+				g.AddEdge(Edge{Start: vertex(row, column), End: vertex(row+1, column), Weight: weight})
+			}
+		}
+	}
+	return g
+}
+
+// linearScanShortestPaths is Dijkstra's algorithm as it was before
+// the VertexHeap, scanning all vertices for the closest one in every
+// step. The benchmarks compare ShortestPaths against it.
+func linearScanShortestPaths(d *DirectedGraph, source Vertex) map[Vertex]int64 {
+	dists := map[Vertex]int64{source: 0}
+	done := make(map[Vertex]bool)
+	for {
+		var u Vertex
+		found := false
+		for v, dist := range dists {
+			if !done[v] && (!found || dist < dists[u]) {
+				u, found = v, true
+			}
+		}
+		if !found {
+			return dists
+		}
+		done[u] = true
+		for _, e := range d.edges[u] {
+			if alt := dists[u] + e.Weight; !done[e.End] {
+				if old, ok := dists[e.End]; !ok || alt < old {
+					dists[e.End] = alt
+				}
+			}
+		}
+	}
+}
+
+func TestShortestPaths(t *testing.T) {
+	g := loadTestGraph(t, "benchmark2.csv")
+
+	tests := []struct {
+		target    string
+		distance  int64
+		reachable bool
+		path      []string // IDs of the edges on the path
+	}{
+		{"n1", 0, true, []string{}},
+		{"n0", 1, true, []string{"e10"}},
+		{"n3", 3, true, []string{"e13"}},
+		{"n4", 10, true, []string{"e10", "e04"}},
+		{"n6", 7, true, []string{"e12", "e26"}},
+		{"n7", 18, true, []string{"e10", "e04", "e47"}},
+		{"n5", 0, false, nil},
+	}
+
+	tree, err := g.ShortestPaths(Vertex{ID: "n1"})
+	if err != nil {
+		t.Fatalf("ShortestPaths failed: %s", err)
+	}
+	for _, test := range tests {
+		target := Vertex{ID: test.target}
+		distance, ok := tree.DistanceTo(target)
+		if ok != test.reachable || distance != test.distance {
+			t.Errorf("DistanceTo(%s) = %d, %t, want %d, %t", test.target, distance, ok, test.distance, test.reachable)
+		}
+
+		path := tree.PathTo(target)
+		if (path == nil) != (test.path == nil) || len(path) != len(test.path) {
+			t.Errorf("PathTo(%s) = %v, want %v", test.target, path, test.path)
+			continue
+		}
+		for i, e := range path {
+			if e.ID != test.path[i] {
+				t.Errorf("PathTo(%s) = %v, want %v", test.target, path, test.path)
+				break
+			}
+		}
+	}
+}
+
+func TestShortestPathTree(t *testing.T) {
+	a, b, c, d := Vertex{ID: "a"}, Vertex{ID: "b"}, Vertex{ID: "c"}, Vertex{ID: "d"}
+	ab := Edge{Start: a, End: b, Weight: 2, ID: "ab"}
+	bc := Edge{Start: b, End: c, Weight: 3, ID: "bc"}
+	tree := &ShortestPathTree{
+		Source:       a,
+		Distances:    map[Vertex]int64{a: 0, b: 2, c: 5},
+		Predecessors: map[Vertex]Edge{b: ab, c: bc},
+	}
+
+	tests := []struct {
+		target    Vertex
+		reachable bool
+		path      []Edge
+	}{
+		{a, true, []Edge{}},
+		{b, true, []Edge{ab}},
+		{c, true, []Edge{ab, bc}},
+		{d, false, nil},
+	}
+
+	for _, test := range tests {
+		if got := tree.Reachable(test.target); got != test.reachable {
+			t.Errorf("Reachable(%s) = %t, want %t", test.target.ID, got, test.reachable)
+		}
+		path := tree.PathTo(test.target)
+		if (path == nil) != (test.path == nil) || fmt.Sprint(path) != fmt.Sprint(test.path) {
+			t.Errorf("PathTo(%s) = %v, want %v", test.target.ID, path, test.path)
+		}
+	}
+}
+
+func TestShortestPathsErrors(t *testing.T) {
+	negative := &DirectedGraph{}
+	negative.AddEdge(Edge{Start: Vertex{ID: "a"}, End: Vertex{ID: "b"}, Weight: -1})
+
+	tests := []struct {
+		name   string
+		g      *DirectedGraph
+		source string
+		err    error
+	}{
+		{"missing source", loadTestGraph(t, "benchmark2.csv"), "n99", ErrVertexNotFound},
+		{"negative weight", negative, "a", ErrNegativeWeight},
+	}
+
+	for _, test := range tests {
+		if _, err := test.g.ShortestPaths(Vertex{ID: test.source}); !errors.Is(err, test.err) {
+			t.Errorf("%s: ShortestPaths returned %v, want %v", test.name, err, test.err)
+		}
+	}
+}
+
+func TestShortestPathsMatchLinearScan(t *testing.T) {
+	for _, name := range []string{"benchmark2.csv", "benchmark3.csv", "edges_3_31601.csv", "edges_5_31601.csv"} {
+		g := loadTestGraph(t, name)
+		for _, source := range g.Vertices() {
+			tree, err := g.ShortestPaths(source)
+			if err != nil {
+				t.Fatalf("%s: ShortestPaths(%s) failed: %s", name, source.ID, err)
+			}
+			want := linearScanShortestPaths(g, source)
+			if len(tree.Distances) != len(want) {
+				t.Errorf("%s: ShortestPaths(%s) reached %d vertices, want %d", name, source.ID, len(tree.Distances), len(want))
+			}
+			for v, dist := range want {
+				if got, _ := tree.DistanceTo(v); got != dist {
+					t.Errorf("%s: distance from %s to %s = %d, want %d", name, source.ID, v.ID, got, dist)
+				}
+			}
+		}
+	}
+}
+
+func TestVertexHeap(t *testing.T) {
+	h := NewVertexHeap(0)
+	for i, priority := range []int64{5, 3, 8, 1, 9, 2} {
+		h.Push(Vertex{ID: strconv.Itoa(i)}, priority)
+	}
+	h.Push(Vertex{ID: "4"}, 0) // Lower the priority of 9
+	h.Push(Vertex{ID: "3"}, 7) // Raise the priority of 1
+
+	want := []int64{0, 2, 3, 5, 7, 8}
+	for _, priority := range want {
+		if _, got := h.Pop(); got != priority {
+			t.Fatalf("Pop returned priority %d, want %d", got, priority)
+		}
+	}
+	if h.Len() != 0 {
+		t.Errorf("Len = %d after popping everything, want 0", h.Len())
+	}
+}
+
+// BenchmarkShortestPaths finds the shortest paths from every vertex
+// of the csv_files inputs with non-negative weights, as the
+// -shortest_path mode does, with the heap and, side by side, with
+// the linear scan it replaced. The linear scan has less overhead on
+// graphs this small, the heap pays off on the larger grid graphs.
+func BenchmarkShortestPaths(b *testing.B) {
+	names := []string{"benchmark2.csv", "benchmark3.csv"}
+	for i := 1; i <= 5; i++ {
+		names = append(names, fmt.Sprintf("edges_%d_31601.csv", i))
+	}
+
+	for _, name := range names {
+		g := loadTestGraph(b, name)
+		b.Run(name+"/heap", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, source := range g.Vertices() {
+					if _, err := g.ShortestPaths(source); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+		b.Run(name+"/linear_scan", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, source := range g.Vertices() {
+					linearScanShortestPaths(g, source)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkShortestPathsGrid and BenchmarkShortestPathsLinearScan
+// compare the heap against the linear scan on graphs larger than
+// the ones in csv_files.
+func BenchmarkShortestPathsGrid(b *testing.B) {
+	for _, n := range []int{30, 60} {
+		g := gridGraph(n)
+		source := Vertex{ID: "0"}
+		b.Run(fmt.Sprintf("%dx%d", n, n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := g.ShortestPaths(source); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkShortestPathsLinearScan(b *testing.B) {
+	for _, n := range []int{30, 60} {
+		g := gridGraph(n)
+		source := Vertex{ID: "0"}
+		b.Run(fmt.Sprintf("%dx%d", n, n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				linearScanShortestPaths(g, source)
+			}
+		})
+	}
+}
+
+// negativeGraph returns a graph of the given "start end weight" edges.
+func negativeGraph(edges ...string) *DirectedGraph {
+	g := &DirectedGraph{}
+	for _, e := range edges {
+		fields := strings.Fields(e)
+		weight, _ := strconv.ParseInt(fields[2], 10, 64)
+		g.AddEdge(Edge{Start: Vertex{ID: fields[0]}, End: Vertex{ID: fields[1]}, Weight: weight, ID: fields[0] + fields[1]})
+	}
+	return g
+}
+
+func TestBellmanFordAndSPFA(t *testing.T) {
+	algorithms := map[string]func(*DirectedGraph, Vertex) (*ShortestPathTree, error){
+		"BellmanFord": (*DirectedGraph).BellmanFord,
+		"SPFA":        (*DirectedGraph).SPFA,
+	}
+
+	tests := []struct {
+		name      string
+		g         *DirectedGraph
+		distances map[string]int64 // nil if there is a negative cycle
+	}{
+		{"negative edge", negativeGraph("a b 4", "a c 2", "c b -3", "b d 1"), map[string]int64{"a": 0, "b": -1, "c": 2, "d": 0}},
+		{"unreachable negative cycle", negativeGraph("a b 1", "d e -1", "e d -1"), map[string]int64{"a": 0, "b": 1}},
+		{"negative cycle", negativeGraph("a b 1", "b c -2", "c b 1", "c d 1"), nil},
+		{"negative loop", negativeGraph("a b 1", "b b -1"), nil},
+	}
+
+	for _, test := range tests {
+		for name, algorithm := range algorithms {
+			tree, err := algorithm(test.g, Vertex{ID: "a"})
+			if test.distances == nil {
+				var cycleErr *NegativeCycleError
+				if !errors.As(err, &cycleErr) {
+					t.Errorf("%s: %s returned %v, want a negative cycle", test.name, name, err)
+					continue
+				}
+				var total int64
+				for i, e := range cycleErr.Cycle {
+					total += e.Weight
+					if next := cycleErr.Cycle[(i+1)%len(cycleErr.Cycle)]; e.End != next.Start {
+						t.Errorf("%s: %s returned a broken cycle %v", test.name, name, cycleErr.Cycle)
+					}
+				}
+				if len(cycleErr.Cycle) == 0 || total >= 0 {
+					t.Errorf("%s: %s returned cycle %v of weight %d", test.name, name, cycleErr.Cycle, total)
+				}
+				continue
+			}
+
+			if err != nil {
+				t.Errorf("%s: %s failed: %s", test.name, name, err)
+				continue
+			}
+			if len(tree.Distances) != len(test.distances) {
+				t.Errorf("%s: %s reached %d vertices, want %d", test.name, name, len(tree.Distances), len(test.distances))
+			}
+			for id, want := range test.distances {
+				if got, _ := tree.DistanceTo(Vertex{ID: id}); got != want {
+					t.Errorf("%s: %s distance to %s = %d, want %d", test.name, name, id, got, want)
+				}
+			}
+		}
+	}
+}
+
+// TestBellmanFordMatchesShortestPaths compares all three algorithms
+// on the csv_files inputs.
+func TestBellmanFordMatchesShortestPaths(t *testing.T) {
+	for _, name := range []string{"benchmark2.csv", "benchmark3.csv", "edges_1_31601.csv", "edges_6_31601.csv"} {
+		g := loadTestGraph(t, name)
+		for _, source := range g.Vertices() {
+			bellmanFord, bfErr := g.BellmanFord(source)
+			spfa, spfaErr := g.SPFA(source)
+			if (bfErr == nil) != (spfaErr == nil) {
+				t.Fatalf("%s: from %s BellmanFord returned %v and SPFA %v", name, source.ID, bfErr, spfaErr)
+			}
+			if bfErr != nil {
+				continue
+			}
+			trees := []*ShortestPathTree{spfa}
+			if dijkstra, err := g.ShortestPaths(source); err == nil {
+				trees = append(trees, dijkstra)
+			}
+			for _, tree := range trees {
+				if fmt.Sprint(tree.Distances) != fmt.Sprint(bellmanFord.Distances) {
+					t.Errorf("%s: distances from %s are %v, want %v", name, source.ID, tree.Distances, bellmanFord.Distances)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkBellmanFord(b *testing.B) {
+	g := gridGraph(30)
+	for i := 0; i < b.N; i++ {
+		if _, err := g.BellmanFord(Vertex{ID: "0"}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSPFA(b *testing.B) {
+	g := gridGraph(30)
+	for i := 0; i < b.N; i++ {
+		if _, err := g.SPFA(Vertex{ID: "0"}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,34 +1,128 @@
-package main
+package graph
 
 import (
-	"encoding/csv"
 	"fmt"
 	"io"
 	"math"
-	"math/rand"
 	"os"
-	"strconv"
-	"time"
+	"slices"
 )
 
-type UndirectedGraph struct {
-	edges    map[Vertex][]Edge // [Start]Edges
-	edgeList []Edge
-	vertices []Vertex
+// UndirectedGraphOf is a weighted graph where every edge can be
+// traversed in both directions. Each edge is stored in both
+// directions in edges, and once (as first added) in edgeList.
+//
+// The zero value is an empty graph in which AddEdge only drops edges
+// identical to one already in the graph (in either direction). A graph
+// made by NewUndirectedMultigraph instead identifies edges by their ID,
+// see IsMultigraph.
+type UndirectedGraphOf[K comparable, W Number] struct {
+	edges      map[VertexOf[K]][]EdgeOf[K, W] // [Start]Edges
+	edgeList   []EdgeOf[K, W]
+	vertices   []VertexOf[K]
+	index      map[VertexOf[K]]int       // [Vertex]Position in vertices
+	edgeSet    map[EdgeOf[K, W]]bool     // Every edge in edges, for quick lookups
+	edgesByID  map[string][]EdgeOf[K, W] // [ID]Edges, as stored in edgeList
+	multigraph bool
+
+	vertexAttributes map[VertexOf[K]]Attributes
+	edgeAttributes   map[EdgeOf[K, W]]Attributes // Shared by both directions of an edge
 }
 
-func (g *UndirectedGraph) VertexCount() int {
+// UndirectedGraph is an undirected graph with string
+// vertex IDs and int64 weights.
+type UndirectedGraph = UndirectedGraphOf[string, int64]
+
+// NewUndirectedMultigraph returns an empty undirected multigraph.
+func NewUndirectedMultigraph() *UndirectedGraph {
+	return NewUndirectedMultigraphOf[string, int64]()
+}
+
+// NewUndirectedMultigraphOf returns an empty undirected multigraph.
+func NewUndirectedMultigraphOf[K comparable, W Number]() *UndirectedGraphOf[K, W] {
+	return &UndirectedGraphOf[K, W]{multigraph: true}
+}
+
+// IsMultigraph reports whether the graph is a multigraph. In a multigraph
+// edges are identified by their ID: any number of parallel edges may
+// connect the same vertices as long as their IDs differ, while adding
+// an edge with the ID of an existing edge has no effect. Edges without
+// an ID are only dropped if identical to an existing edge.
+//
+// Graphs which are not multigraphs keep parallel edges too, as long as
+// they differ in their ID or another field: only an edge identical to
+// one already in the graph is dropped.
+func (g *UndirectedGraphOf[K, W]) IsMultigraph() bool {
+	return g.multigraph
+}
+
+// Vertices returns the vertices of the graph in insertion order.
+// The returned slice must not be modified. It keeps the vertices
+// it has when vertices are removed from the graph.
+func (g *UndirectedGraphOf[K, W]) Vertices() []VertexOf[K] {
+	return g.vertices
+}
+
+// HasVertex reports whether v is part of the graph.
+func (g *UndirectedGraphOf[K, W]) HasVertex(v VertexOf[K]) bool {
+	_, ok := g.index[v]
+	return ok
+}
+
+func (g *UndirectedGraphOf[K, W]) VertexCount() int {
 	return len(g.vertices)
 }
 
-func (g *UndirectedGraph) EdgeCount() int {
-	return len(g.edges)
+// EdgeCount returns the number of edges in the graph, counting
+// each edge once even though it can be traversed both ways.
+func (g *UndirectedGraphOf[K, W]) EdgeCount() int {
+	return len(g.edgeList)
+}
+
+// Edges returns every edge of the graph once,
+// in the direction it was first added.
+func (g *UndirectedGraphOf[K, W]) Edges() []EdgeOf[K, W] {
+	edges := make([]EdgeOf[K, W], len(g.edgeList))
+	copy(edges, g.edgeList)
+	return edges
+}
+
+// OutEdges returns the edges incident to v, all
+// turned so that they start at v.
+// The returned slice must not be modified.
+func (g *UndirectedGraphOf[K, W]) OutEdges(v VertexOf[K]) []EdgeOf[K, W] {
+	return g.edges[v]
 }
 
-func (g *UndirectedGraph) String() string {
+// InEdges returns the edges incident to v, all
+// turned so that they end at v.
+func (g *UndirectedGraphOf[K, W]) InEdges(v VertexOf[K]) []EdgeOf[K, W] {
+	var edges []EdgeOf[K, W]
+	for _, edge := range g.edges[v] {
+		edges = append(edges, edge.Reverse())
+	}
+
+	return edges
+}
+
+// Degree returns the number of edges incident
+// to v, counting self loops twice.
+func (g *UndirectedGraphOf[K, W]) Degree(v VertexOf[K]) int {
+	degree := 0
+	for _, edge := range g.edges[v] {
+		degree++
+		if edge.End == v {
+			degree++ // A self loop
+		}
+	}
+
+	return degree
+}
+
+func (g *UndirectedGraphOf[K, W]) String() string {
 	s := "\n"
 	for k, v := range g.edges {
-		s += fmt.Sprintf("%s\n\t%v\n", k.id, v)
+		s += fmt.Sprintf("%v\n\t%v\n", k.ID, v)
 	}
 
 	return s
@@ -37,10 +131,10 @@ func (g *UndirectedGraph) String() string {
 // vertexNeighbours returns a list of v's
 // neighbour vertices to which it is directly
 // connected with an edge.
-func (g *UndirectedGraph) vertexNeighbours(v Vertex) Vertices {
-	var vertices Vertices
+func (g *UndirectedGraphOf[K, W]) vertexNeighbours(v VertexOf[K]) VerticesOf[K] {
+	var vertices VerticesOf[K]
 	for _, edge := range g.edges[v] {
-		vertices = append(vertices, edge.end)
+		vertices = append(vertices, edge.End)
 	}
 
 	return vertices
@@ -49,18 +143,18 @@ func (g *UndirectedGraph) vertexNeighbours(v Vertex) Vertices {
 // edgeNeighbours returns all the edges neighbouring the given edge.
 // It will only return one "version" of each Edge, not both the
 // forwards and backwards version.
-func (g *UndirectedGraph) edgeNeighbours(e Edge) []Edge {
-	edges := make(map[Edge]bool)
+func (g *UndirectedGraphOf[K, W]) edgeNeighbours(e EdgeOf[K, W]) []EdgeOf[K, W] {
+	edges := make(map[EdgeOf[K, W]]bool)
 
 	for _, edge := range g.edgeList {
-		if e.start == edge.start || e.start == edge.end ||
-			e.end == edge.start || e.end == edge.end {
+		if e.Start == edge.Start || e.Start == edge.End ||
+			e.End == edge.Start || e.End == edge.End {
 			edges[edge] = true
 
 		}
 	}
 
-	var result []Edge
+	var result []EdgeOf[K, W]
 	for edge := range edges {
 		result = append(result, edge)
 	}
@@ -68,138 +162,329 @@ func (g *UndirectedGraph) edgeNeighbours(e Edge) []Edge {
 	return result
 }
 
-func (g *UndirectedGraph) AddEdge(e Edge) {
+func (g *UndirectedGraphOf[K, W]) AddEdge(e EdgeOf[K, W]) {
 	if g.edges == nil {
 		// Lazily initialize
-		g.edges = make(map[Vertex][]Edge)
+		g.edges = make(map[VertexOf[K]][]EdgeOf[K, W])
+		g.edgeSet = make(map[EdgeOf[K, W]]bool)
+		g.edgesByID = make(map[string][]EdgeOf[K, W])
 	}
 
-	g.edgeList = append(g.edgeList, e)
+	if g.multigraph && e.ID != "" && len(g.edgesByID[e.ID]) > 0 {
+		return // Already have an edge with this ID
+	}
 
-	reverseEdge := Edge{start: e.end, end: e.start, weight: e.weight, id: e.id}
-	edgeFound := false
-	reverseEdgeFound := false
+	// Add the edge and its reverse, unless they are already in the graph
+	reverseEdge := e.Reverse()
+	forward, backward := g.edgeSet[e], g.edgeSet[reverseEdge]
+	if !forward && !backward {
+		g.edgeList = append(g.edgeList, e)
+		g.edgesByID[e.ID] = append(g.edgesByID[e.ID], e)
+	}
+	if !forward {
+		g.edgeSet[e] = true
+		g.edges[e.Start] = append(g.edges[e.Start], e)
+	}
+	if !backward && reverseEdge != e {
+		g.edgeSet[reverseEdge] = true
+		g.edges[e.End] = append(g.edges[e.End], reverseEdge)
+	}
 
-	if edges, ok := g.edges[e.start]; ok {
-		// Check that edge is not already in list
-		for _, existingEdge := range edges {
-			if existingEdge == e {
-				edgeFound = true
-			}
-		}
+	// Add the two vertices too, if they did not exist already
+	g.AddVertex(e.Start)
+	g.AddVertex(e.End)
+}
+
+// Grow makes room for the given numbers of vertices and edges to be
+// added, so that the graph does not have to grow step by step while
+// they are added, e.g. when the size of a file is known up front.
+func (g *UndirectedGraphOf[K, W]) Grow(vertices, edges int) {
+	g.edges = growMap(g.edges, vertices)
+	g.edgeList = slices.Grow(g.edgeList, edges)
+	// Both directions of every edge
+	g.edgeSet = growMap(g.edgeSet, 2*edges)
+	g.edgesByID = growMap(g.edgesByID, edges)
+	g.index = growMap(g.index, vertices)
+	g.vertices = slices.Grow(g.vertices, vertices)
+}
+
+// AddVertex adds v to the graph unless it is already part of it.
+// Vertices added this way may stay isolated, without any edges.
+func (g *UndirectedGraphOf[K, W]) AddVertex(v VertexOf[K]) {
+	if g.index == nil {
+		// Lazily initialize
+		g.index = make(map[VertexOf[K]]int)
 	}
 
-	if edges, ok := g.edges[e.end]; ok {
-		// Check that reverse edge is not already in list
-		for _, existingEdge := range edges {
-			if existingEdge == reverseEdge {
-				reverseEdgeFound = true
-			}
-		}
+	if _, ok := g.index[v]; !ok {
+		g.index[v] = len(g.vertices)
+		g.vertices = append(g.vertices, v)
 	}
+}
+
+// RemoveEdge removes e from the graph, in both directions, and
+// reports whether it was part of it. The edge may be given in
+// either direction. The vertices of the edge are left in the graph.
+func (g *UndirectedGraphOf[K, W]) RemoveEdge(e EdgeOf[K, W]) bool {
+	if !g.edgeSet[e] {
+		return false
+	}
+
+	g.removeEdges(map[EdgeOf[K, W]]bool{e: true})
+	return true
+}
 
-	if edgeFound == false {
-		// Add the edge
-		g.edges[e.start] = append(g.edges[e.start], e)
+// EdgeByID returns the edge with the given ID, in the direction it
+// was added, and false if there is none. Outside of a multigraph
+// several edges may share an ID, in which case the first one added
+// is returned.
+func (g *UndirectedGraphOf[K, W]) EdgeByID(id string) (EdgeOf[K, W], bool) {
+	edges := g.edgesByID[id]
+	if len(edges) == 0 {
+		return EdgeOf[K, W]{}, false
 	}
 
-	if reverseEdgeFound == false {
-		// Add the reverse edge
-		g.edges[e.end] = append(g.edges[e.end], reverseEdge)
+	return edges[0], true
+}
+
+// RemoveEdgeByID removes the edges with the given ID from the
+// graph, and reports whether there were any.
+func (g *UndirectedGraphOf[K, W]) RemoveEdgeByID(id string) bool {
+	removed := make(map[EdgeOf[K, W]]bool)
+	for _, e := range g.edgesByID[id] {
+		removed[e] = true
 	}
+	g.removeEdges(removed)
 
-	// Check whether the two vertices already existed. If not, add them too
-	startExists := false
-	endExists := false
-	for _, existingVertex := range g.vertices {
-		if existingVertex == e.start {
-			startExists = true
-		} else if existingVertex == e.end {
-			endExists = true
+	return len(removed) > 0
+}
+
+// RemoveVertex removes v and every edge incident to it from
+// the graph, and reports whether v was part of it.
+func (g *UndirectedGraphOf[K, W]) RemoveVertex(v VertexOf[K]) bool {
+	i, ok := g.index[v]
+	if !ok {
+		return false
+	}
+
+	incident := make(map[EdgeOf[K, W]]bool)
+	for _, e := range g.edges[v] {
+		incident[e] = true
+	}
+	g.removeEdges(incident)
+
+	// Remove the vertex, keeping the rest in insertion order. The
+	// slice is copied, as Vertices may have returned the old one.
+	delete(g.index, v)
+	delete(g.vertexAttributes, v)
+	g.vertices = append(g.vertices[:i:i], g.vertices[i+1:]...)
+	for j := i; j < len(g.vertices); j++ {
+		g.index[g.vertices[j]] = j
+	}
+
+	return true
+}
+
+// removeEdges removes the given edges, in both directions,
+// from edges, edgeSet and edgeList.
+func (g *UndirectedGraphOf[K, W]) removeEdges(removed map[EdgeOf[K, W]]bool) {
+	var both []EdgeOf[K, W]
+	for e := range removed {
+		both = append(both, e, e.Reverse())
+	}
+
+	for _, e := range both {
+		removed[e] = true
+		delete(g.edgeSet, e)
+		delete(g.edgeAttributes, e)
+		g.edges[e.Start] = removeEdge(g.edges[e.Start], e)
+		if len(g.edges[e.Start]) == 0 {
+			delete(g.edges, e.Start)
+		}
+		// Only one of the directions is found here
+		g.edgesByID[e.ID] = removeEdge(g.edgesByID[e.ID], e)
+		if len(g.edgesByID[e.ID]) == 0 {
+			delete(g.edgesByID, e.ID)
 		}
 	}
 
-	if startExists == false {
-		g.vertices = append(g.vertices, e.start)
+	// Rebuild edgeList in a single pass
+	edgeList := g.edgeList[:0]
+	for _, e := range g.edgeList {
+		if !removed[e] {
+			edgeList = append(edgeList, e)
+		}
 	}
-	if endExists == false {
-		g.vertices = append(g.vertices, e.end)
+	g.edgeList = edgeList
+}
+
+// VertexAttributes returns the attributes of v, or nil if it has none.
+// The returned map must not be modified, use SetVertexAttribute.
+func (g *UndirectedGraphOf[K, W]) VertexAttributes(v VertexOf[K]) Attributes {
+	return g.vertexAttributes[v]
+}
+
+// SetVertexAttribute sets the named attribute of v, and reports
+// false (leaving the graph unchanged) if v is not part of the graph.
+func (g *UndirectedGraphOf[K, W]) SetVertexAttribute(v VertexOf[K], name string, value any) bool {
+	if !g.HasVertex(v) {
+		return false
 	}
+
+	setAttribute(&g.vertexAttributes, v, name, value)
+	return true
 }
 
-// NewUndirectedGraphFromFile reads in a graph from the given path to a CSV.
-// It expects values in the form [startNodeID, endNodeID, weight, edgeID]
-// for every row. It will skip rows where the length is not 4 or the third
-// value can not be parsed as an integer.
-// It returns an UndirectedGraph, built from the values in the csv file
-// It will throw an error if the file can not be read for some reason.
-func NewUndirectedGraphFromFile(filePath string, valueSeparator rune) (*UndirectedGraph, error) {
-	g := UndirectedGraph{}
+// EdgeAttributes returns the attributes of e, given in either
+// direction, or nil if it has none. The returned map must not be
+// modified, use SetEdgeAttribute.
+func (g *UndirectedGraphOf[K, W]) EdgeAttributes(e EdgeOf[K, W]) Attributes {
+	return g.edgeAttributes[e]
+}
 
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
+// SetEdgeAttribute sets the named attribute of e, given in either
+// direction, and reports false (leaving the graph unchanged) if e
+// is not part of the graph.
+func (g *UndirectedGraphOf[K, W]) SetEdgeAttribute(e EdgeOf[K, W], name string, value any) bool {
+	if !g.edgeSet[e] {
+		return false
 	}
-	// automatically call Close() at the end of current method
-	defer file.Close()
 
-	reader := csv.NewReader(file)
-	reader.Comma = valueSeparator
+	setAttribute(&g.edgeAttributes, e, name, value)
+	g.edgeAttributes[e.Reverse()] = g.edgeAttributes[e]
+	return true
+}
 
-	// options are available at:
-	// http://golang.org/src/pkg/encoding/csv/reader.go?s=3213:3671#L94
-	for {
-		// read just one record, but we could ReadAll() as well
-		record, err := reader.Read()
-		// EOF is fitted into err
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			return nil, err
+// Subgraph returns the subgraph induced by the given vertices: those
+// of them which are part of the graph, and every edge between them.
+// Attributes are copied along, and the result is a multigraph if
+// the graph is one.
+func (g *UndirectedGraphOf[K, W]) Subgraph(vertices []VertexOf[K]) *UndirectedGraphOf[K, W] {
+	sub := &UndirectedGraphOf[K, W]{multigraph: g.multigraph}
+	for _, v := range vertices {
+		if g.HasVertex(v) {
+			sub.addVertexFrom(g, v)
 		}
+	}
+	for _, e := range g.edgeList {
+		if sub.HasVertex(e.Start) && sub.HasVertex(e.End) {
+			sub.addEdgeFrom(g, e)
+		}
+	}
 
-		// Some graphs leave out the weight
-		if len(record) == 3 {
-			// Dig out the values, skip the row if invalid values
-			start := Vertex{id: record[0]}
-			end := Vertex{id: record[1]}
-			id := record[2]
-			// Create & add Edge
-			e := Edge{start: start, end: end, weight: -1, id: id}
-			g.AddEdge(e)
+	return sub
+}
 
-			continue
+// EdgeSubgraph returns the subgraph made up of the given edges which
+// are part of the graph, and their endpoints. Attributes are copied
+// along, and the result is a multigraph if the graph is one.
+func (g *UndirectedGraphOf[K, W]) EdgeSubgraph(edges []EdgeOf[K, W]) *UndirectedGraphOf[K, W] {
+	sub := &UndirectedGraphOf[K, W]{multigraph: g.multigraph}
+	for _, e := range edges {
+		if g.edgeSet[e] {
+			sub.addVertexFrom(g, e.Start)
+			sub.addVertexFrom(g, e.End)
+			sub.addEdgeFrom(g, e)
 		}
+	}
 
-		// We're expecting three or four values
-		if len(record) != 3 && len(record) != 4 {
-			continue
-		}
+	return sub
+}
 
-		// Dig out the values, skip the row if invalid values
-		start := Vertex{id: record[0]}
-		end := Vertex{id: record[1]}
-		weight, err := strconv.ParseInt(record[2], 0, 64)
-		if err != nil {
-			// log.Printf("Skipping row '%v', not a valid edge!\n", record)
-			continue
-		}
-		id := record[3]
+// addVertexFrom adds v to the graph together with its attributes in h.
+func (g *UndirectedGraphOf[K, W]) addVertexFrom(h *UndirectedGraphOf[K, W], v VertexOf[K]) {
+	g.AddVertex(v)
+	for name, value := range h.vertexAttributes[v] {
+		g.SetVertexAttribute(v, name, value)
+	}
+}
 
-		// Create & add Edge
-		e := Edge{start: start, end: end, weight: weight, id: id}
-		g.AddEdge(e)
+// addEdgeFrom adds e to the graph together with its attributes in h.
+func (g *UndirectedGraphOf[K, W]) addEdgeFrom(h *UndirectedGraphOf[K, W], e EdgeOf[K, W]) {
+	g.AddEdge(e)
+	for name, value := range h.edgeAttributes[e] {
+		g.SetEdgeAttribute(e, name, value)
+	}
+}
 
+// ReadUndirectedGraph reads in a graph from r, in the format given by
+// opts.Format. For CSV, the default, it expects values in the form
+// [startNodeID, endNodeID, weight, edgeID] for every row, or
+// [startNodeID, endNodeID, edgeID] for graphs without weights,
+// unless opts.Fields says otherwise.
+// If the first row is a header naming the columns (e.g. "Vertex1",
+// "Vertex2", "capacity", "id"), the values are instead read from the
+// named columns into the matching Edge fields, see LoadOptions.Columns.
+// Without a capacity column the weight is used as the capacity.
+// Without an id column the edges are numbered "e1", "e2" and so on.
+// Other named columns are kept as attributes of the edge, or of its
+// start or end vertex if named e.g. "Vertex1.label" or "Vertex2.x".
+//
+// The other formats are described with their Format constants.
+// Input of any format is decompressed if it is gzip compressed.
+//
+// Rows which are not valid edges make it fail with a *ParseError in
+// strict mode, and are otherwise skipped and returned as warnings.
+// It will also return an error if r can not be read.
+func ReadUndirectedGraph(r io.Reader, opts LoadOptions) (*UndirectedGraph, []*ParseError, error) {
+	return ReadUndirectedGraphOf[int64](r, opts)
+}
+
+// ReadUndirectedGraphOf reads in a graph like ReadUndirectedGraph, parsing its
+// weights, capacities and costs as W.
+func ReadUndirectedGraphOf[W Number](r io.Reader, opts LoadOptions) (*UndirectedGraphOf[string, W], []*ParseError, error) {
+	g := UndirectedGraphOf[string, W]{multigraph: opts.Multigraph}
+	warnings, err := readGraph[W](&g, r, "", opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &g, warnings, nil
+}
+
+// LoadUndirectedGraph reads in a graph from the file at filePath, in the format
+// given by its extension unless opts.Format is set, see ReadUndirectedGraph.
+func LoadUndirectedGraph(filePath string, opts LoadOptions) (*UndirectedGraph, []*ParseError, error) {
+	return LoadUndirectedGraphOf[int64](filePath, opts)
+}
+
+// LoadUndirectedGraphOf reads in a graph like LoadUndirectedGraph, parsing its
+// weights, capacities and costs as W.
+func LoadUndirectedGraphOf[W Number](filePath string, opts LoadOptions) (*UndirectedGraphOf[string, W], []*ParseError, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	// automatically call Close() at the end of current method
+	defer file.Close()
+
+	if opts.Format == "" {
+		opts.Format, _ = FormatFromPath(filePath)
+	}
+
+	g := UndirectedGraphOf[string, W]{multigraph: opts.Multigraph}
+	warnings, err := readGraph[W](&g, file, filePath, opts)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return &g, nil
+	return &g, warnings, nil
+}
+
+// NewUndirectedGraphFromFile reads in a graph from the given path to a CSV,
+// skipping rows which are not valid edges. See ReadUndirectedGraph.
+// It returns an UndirectedGraph, built from the values in the csv file
+// It will throw an error if the file can not be read for some reason.
+func NewUndirectedGraphFromFile(filePath string, valueSeparator rune) (*UndirectedGraph, error) {
+	g, _, err := LoadUndirectedGraph(filePath, LoadOptions{Separator: valueSeparator})
+	return g, err
 }
 
 // PrimMST implements Prim's algorithm. Shamelessly implemented
 // as per it's Wikipedia description: http://en.wikipedia.org/wiki/Prim's_algorithm
-func (g *UndirectedGraph) PrimMST(start Vertex) []Edge {
-	vNew := Vertices{start}
-	var eNew []Edge
+func (g *UndirectedGraphOf[K, W]) PrimMST(start VertexOf[K]) []EdgeOf[K, W] {
+	vNew := VerticesOf[K]{start}
+	var eNew []EdgeOf[K, W]
 
 	var count = 0
 
@@ -210,22 +495,24 @@ func (g *UndirectedGraph) PrimMST(start Vertex) []Edge {
 			break
 		}
 
-		var minWeightCandidate int64 = math.MaxInt64
-		var vertexCandidate Vertex
-		var edgeCandidate Edge
+		var minWeightCandidate W
+		var vertexCandidate VertexOf[K]
+		var edgeCandidate EdgeOf[K, W]
+		found := false
 
 		for _, v := range vNew {
 			for _, edge := range g.edges[v] {
-				if vNew.contains(edge.end) == false {
-					if edge.weight < minWeightCandidate {
-						vertexCandidate = edge.end
+				if vNew.contains(edge.End) == false {
+					if !found || edge.Weight < minWeightCandidate {
+						vertexCandidate = edge.End
 						edgeCandidate = edge
-						minWeightCandidate = edge.weight
+						minWeightCandidate = edge.Weight
+						found = true
 					}
 				}
 			}
 		}
-		if vertexCandidate.id != "" {
+		if found {
 			vNew = append(vNew, vertexCandidate)
 			eNew = append(eNew, edgeCandidate)
 		}
@@ -240,16 +527,18 @@ func (g *UndirectedGraph) PrimMST(start Vertex) []Edge {
 // It will loop over the list of vertices and set the color
 // to the smallest integer not used by one of the vertex's
 // neighbours until no more optimisations can be made.
-func (g *UndirectedGraph) VertexColors() map[Vertex]int {
+// Vertices are visited in insertion order, so the result
+// is the same on every run.
+func (g *UndirectedGraphOf[K, W]) VertexColors() map[VertexOf[K]]int {
 	// Track vertex colors & start off at math.Maxint32
-	vertexColors := make(map[Vertex]int)
+	vertexColors := make(map[VertexOf[K]]int)
 	for _, v := range g.vertices {
 		vertexColors[v] = math.MaxInt32
 	}
 
 	for {
 		graphChangedDuringCurrentPass := false
-		for vertex := range vertexColors {
+		for _, vertex := range g.vertices {
 			// Get list of neighbour vertices
 			neighbours := g.vertexNeighbours(vertex)
 
@@ -297,16 +586,18 @@ func (g *UndirectedGraph) VertexColors() map[Vertex]int {
 // It will loop over the list of edges and set the color
 // to the smallest integer not used by one of the edge's
 // neighbours until no more optimisations can be made.
-func (g *UndirectedGraph) EdgeColors() map[Edge]int {
+// Edges are visited in insertion order, so the result
+// is the same on every run.
+func (g *UndirectedGraphOf[K, W]) EdgeColors() map[EdgeOf[K, W]]int {
 	// Track edge colors & start off at math.Maxint32
-	edgeColors := make(map[Edge]int)
+	edgeColors := make(map[EdgeOf[K, W]]int)
 	for _, e := range g.edgeList {
 		edgeColors[e] = math.MaxInt32
 	}
 
 	for {
 		graphChangedDuringCurrentPass := false
-		for edge := range edgeColors {
+		for _, edge := range g.edgeList {
 			// Get list of neighbour vertices
 			neighbours := g.edgeNeighbours(edge)
 
@@ -349,10 +640,14 @@ func (g *UndirectedGraph) EdgeColors() map[Edge]int {
 
 // addableEdgesRemaining returns a slice containing all the edges not
 // yet in maxCardEdges nor a neighbour to one of the maxCardEdges.
-func (g *UndirectedGraph) addableEdgesRemaining(maxCardEdges Edges) Edges {
-	var addableEdges Edges
+func (g *UndirectedGraphOf[K, W]) addableEdgesRemaining(maxCardEdges EdgesOf[K, W]) EdgesOf[K, W] {
+	var addableEdges EdgesOf[K, W]
 
 	for _, remainingEdge := range g.edgeList {
+		if remainingEdge.Start == remainingEdge.End {
+			// Self loops can not be matched
+			continue
+		}
 		if maxCardEdges.contains(remainingEdge) {
 			// Already added this edge
 			continue
@@ -360,10 +655,10 @@ func (g *UndirectedGraph) addableEdgesRemaining(maxCardEdges Edges) Edges {
 
 		neighbourFound := false
 		for _, existingEdge := range maxCardEdges {
-			if existingEdge.start == remainingEdge.start ||
-				existingEdge.start == remainingEdge.end ||
-				existingEdge.end == remainingEdge.start ||
-				existingEdge.end == remainingEdge.end {
+			if existingEdge.Start == remainingEdge.Start ||
+				existingEdge.Start == remainingEdge.End ||
+				existingEdge.End == remainingEdge.Start ||
+				existingEdge.End == remainingEdge.End {
 				// If this Edge is neighbour to one of the already added edges...
 				neighbourFound = true
 				break
@@ -381,11 +676,11 @@ func (g *UndirectedGraph) addableEdgesRemaining(maxCardEdges Edges) Edges {
 // not yet in maxCardEdges nor a neighbour to one of the maxCardEdges
 // which are also "mono" edges, as in edges where the start vertex is
 // only connected with a single edge to the graph.
-func (g *UndirectedGraph) addableMonoEdgesRemaining(maxCardEdges Edges) Edges {
-	var monoEdges Edges
+func (g *UndirectedGraphOf[K, W]) addableMonoEdgesRemaining(maxCardEdges EdgesOf[K, W]) EdgesOf[K, W] {
+	var monoEdges EdgesOf[K, W]
 
 	for _, edge := range g.edgeList {
-		if len(g.edges[edge.start]) == 1 {
+		if len(g.edges[edge.Start]) == 1 && edge.Start != edge.End {
 			if maxCardEdges.contains(edge) {
 				// Already added this edge
 				continue
@@ -393,10 +688,10 @@ func (g *UndirectedGraph) addableMonoEdgesRemaining(maxCardEdges Edges) Edges {
 
 			neighbourFound := false
 			for _, existingEdge := range maxCardEdges {
-				if existingEdge.start == edge.start ||
-					existingEdge.start == edge.end ||
-					existingEdge.end == edge.start ||
-					existingEdge.end == edge.end {
+				if existingEdge.Start == edge.Start ||
+					existingEdge.Start == edge.End ||
+					existingEdge.End == edge.Start ||
+					existingEdge.End == edge.End {
 					// If this Edge is neighbour to one of the already added edges...
 					neighbourFound = true
 					break
@@ -411,24 +706,37 @@ func (g *UndirectedGraph) addableMonoEdgesRemaining(maxCardEdges Edges) Edges {
 	return monoEdges
 }
 
-// maxCardMatching tries to find a maximum-cardinality edge
+// MatchingOptions controls GreedyMatching.
+type MatchingOptions struct {
+	// Iterations is the number of random maximal matchings
+	// to try at most, after the first one.
+	Iterations int
+	RandomOptions
+}
+
+// GreedyMatching tries to find a maximum-cardinality edge
 // matching in a connected undirected graph. The function
 // uses a greedy, iterative algorithm, and as such it will
 // only work for connected graphs and is not guaranteed to
 // always find the absolute maximum edge matching.
-func (g *UndirectedGraph) maxCardMatching(iterationsMax int) Edges {
+// BlossomMatching always finds one, in any graph.
+//
+// It generates random maximal matchings until one is found which
+// covers all vertices (but one), or opts.Iterations are used up.
+// The random choices are made as given by opts.RandomOptions, so
+// the same options give the same result. Self loops are never
+// part of a matching.
+func (g *UndirectedGraphOf[K, W]) GreedyMatching(opts MatchingOptions) EdgesOf[K, W] {
 	iterations := 0
-	var bestResultSoFar Edges
+	var bestResultSoFar EdgesOf[K, W]
+	r := opts.rand()
 
 	for {
 		// Generate maximal matchings until we find a maximum matching
-		// or we run out of iterationsMax.
+		// or we run out of iterations.
 
 		iterations += 1
-		var maxCardEdges Edges
-
-		// Create and seed a random generator.
-		r := rand.New(rand.NewSource(time.Now().UnixNano()))
+		var maxCardEdges EdgesOf[K, W]
 
 		// Keep adding random Edges until there are no more edges that can be added.
 		for {
@@ -455,6 +763,11 @@ func (g *UndirectedGraph) maxCardMatching(iterationsMax int) Edges {
 
 		// fmt.Printf("Generated maximal edge matching with %d matches for graph with %d vertices\n", len(maxCardEdges), len(g.vertices))
 
+		if len(maxCardEdges) == 0 {
+			// There are no edges to match
+			return bestResultSoFar
+		}
+
 		if len(g.vertices)/len(maxCardEdges) == 2 {
 			if len(g.vertices)%len(maxCardEdges) == 0 || len(g.vertices)%len(maxCardEdges) == 1 {
 				// We have a set of maximum-cardinality edges including either:
@@ -465,7 +778,7 @@ func (g *UndirectedGraph) maxCardMatching(iterationsMax int) Edges {
 			}
 		}
 
-		if iterations > iterationsMax {
+		if iterations > opts.Iterations {
 			return bestResultSoFar
 		}
 	}
@@ -0,0 +1,268 @@
+package graph
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestUndirectedGraphAddEdge(t *testing.T) {
+	a, b, c := Vertex{ID: "a"}, Vertex{ID: "b"}, Vertex{ID: "c"}
+
+	tests := []struct {
+		name     string
+		edges    []Edge
+		vertices []Vertex
+		count    int // Expected EdgeCount
+		degree   int // Expected Degree of a
+	}{
+		{"single", []Edge{{Start: a, End: b, ID: "1"}}, []Vertex{a, b}, 1, 1},
+		{"identical edges are dropped", []Edge{{Start: a, End: b, ID: "1"}, {Start: a, End: b, ID: "1"}}, []Vertex{a, b}, 1, 1},
+		{"reverse is the same edge", []Edge{{Start: a, End: b, ID: "1"}, {Start: b, End: a, ID: "1"}}, []Vertex{a, b}, 1, 1},
+		{"parallel edges are kept", []Edge{{Start: a, End: b, ID: "1"}, {Start: b, End: a, ID: "2"}}, []Vertex{a, b}, 2, 2},
+		{"vertices in insertion order", []Edge{{Start: c, End: a}, {Start: b, End: c}}, []Vertex{c, a, b}, 2, 1},
+		{"loop", []Edge{{Start: a, End: a}}, []Vertex{a}, 1, 2},
+	}
+
+	for _, test := range tests {
+		g := &UndirectedGraph{}
+		for _, e := range test.edges {
+			g.AddEdge(e)
+		}
+		if got := g.EdgeCount(); got != test.count {
+			t.Errorf("%s: EdgeCount = %d, want %d", test.name, got, test.count)
+		}
+		if got := g.Degree(a); got != test.degree {
+			t.Errorf("%s: Degree(a) = %d, want %d", test.name, got, test.degree)
+		}
+		if got := g.Vertices(); fmt.Sprint(got) != fmt.Sprint(test.vertices) {
+			t.Errorf("%s: Vertices = %v, want %v", test.name, got, test.vertices)
+		}
+		for _, v := range test.vertices {
+			if !g.HasVertex(v) {
+				t.Errorf("%s: HasVertex(%s) = false", test.name, v.ID)
+			}
+		}
+		if g.HasVertex(Vertex{ID: "missing"}) {
+			t.Errorf("%s: HasVertex(missing) = true", test.name)
+		}
+	}
+}
+
+// TestUndirectedGraphAddEdgeMany checks the indexes stay consistent
+// with the edges when adding many edges, in both directions.
+func TestUndirectedGraphAddEdgeMany(t *testing.T) {
+	edges := testEdges(10000)
+	g := &UndirectedGraph{}
+	for _, e := range edges {
+		g.AddEdge(e)
+		g.AddEdge(e.Reverse())
+	}
+
+	if got := g.EdgeCount(); got != len(edges) {
+		t.Errorf("EdgeCount = %d, want %d", got, len(edges))
+	}
+	degrees := 0
+	for i, v := range g.Vertices() {
+		if g.index[v] != i {
+			t.Fatalf("index of %s is %d, want %d", v.ID, g.index[v], i)
+		}
+		degrees += g.Degree(v)
+	}
+	if degrees != 2*len(edges) {
+		t.Errorf("degrees add up to %d, want %d", degrees, 2*len(edges))
+	}
+}
+
+// BenchmarkUndirectedGraphAddEdge adds edges to an empty graph, and to
+// one grown to fit them.
+func BenchmarkUndirectedGraphAddEdge(b *testing.B) {
+	for _, n := range []int{10000, 100000} {
+		edges := testEdges(n)
+		for _, grow := range []bool{false, true} {
+			name := strconv.Itoa(n)
+			if grow {
+				name += "/grown"
+			}
+			b.Run(name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					g := &UndirectedGraph{}
+					if grow {
+						g.Grow(n/4+2, n)
+					}
+					for _, e := range edges {
+						g.AddEdge(e)
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkReadUndirectedGraph reads CSV files of growing size, the
+// time per edge should stay about the same.
+func BenchmarkReadUndirectedGraph(b *testing.B) {
+	for _, n := range []int{10000, 100000} {
+		csv := testCSV(testEdges(n))
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, _, err := ReadUndirectedGraph(bytes.NewReader(csv), LoadOptions{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestUndirectedGraphRemove(t *testing.T) {
+	a, b, c := Vertex{ID: "a"}, Vertex{ID: "b"}, Vertex{ID: "c"}
+	ab := Edge{Start: a, End: b, ID: "ab"}
+	bc := Edge{Start: b, End: c, ID: "bc"}
+	ca := Edge{Start: c, End: a, ID: "ca"}
+	abParallel := Edge{Start: a, End: b, Weight: 1, ID: "ab"}
+
+	tests := []struct {
+		name     string
+		remove   func(g *UndirectedGraph) bool
+		removed  bool
+		vertices string
+		count    int
+	}{
+		{"edge", func(g *UndirectedGraph) bool { return g.RemoveEdge(bc) }, true, "[{a} {b} {c}]", 3},
+		{"reversed edge", func(g *UndirectedGraph) bool { return g.RemoveEdge(bc.Reverse()) }, true, "[{a} {b} {c}]", 3},
+		{"missing edge", func(g *UndirectedGraph) bool { return g.RemoveEdge(Edge{Start: a, End: c}) }, false, "[{a} {b} {c}]", 4},
+		{"edges by ID", func(g *UndirectedGraph) bool { return g.RemoveEdgeByID("ab") }, true, "[{a} {b} {c}]", 2},
+		{"missing ID", func(g *UndirectedGraph) bool { return g.RemoveEdgeByID("xx") }, false, "[{a} {b} {c}]", 4},
+		{"vertex", func(g *UndirectedGraph) bool { return g.RemoveVertex(b) }, true, "[{a} {c}]", 1},
+		{"missing vertex", func(g *UndirectedGraph) bool { return g.RemoveVertex(Vertex{ID: "x"}) }, false, "[{a} {b} {c}]", 4},
+	}
+
+	for _, test := range tests {
+		g := &UndirectedGraph{}
+		for _, e := range []Edge{ab, bc, ca, abParallel} {
+			g.AddEdge(e)
+		}
+		g.SetEdgeAttribute(bc, "label", "x")
+		before := g.Vertices()
+
+		if removed := test.remove(g); removed != test.removed {
+			t.Errorf("%s: removing returned %t, want %t", test.name, removed, test.removed)
+		}
+		if got := fmt.Sprint(before); got != "[{a} {b} {c}]" {
+			t.Errorf("%s: Vertices returned before removing changed to %s", test.name, got)
+		}
+		if got := fmt.Sprint(g.Vertices()); got != test.vertices {
+			t.Errorf("%s: Vertices = %s, want %s", test.name, got, test.vertices)
+		}
+		checkEdges(t, test.name, g, test.count)
+		for i, v := range g.Vertices() {
+			if g.index[v] != i {
+				t.Errorf("%s: index of %s is %d, want %d", test.name, v.ID, g.index[v], i)
+			}
+		}
+		if _, ok := g.EdgeByID("bc"); !ok && (g.EdgeAttributes(bc) != nil || g.EdgeAttributes(bc.Reverse()) != nil) {
+			t.Errorf("%s: the attributes of a removed edge are kept", test.name)
+		}
+	}
+}
+
+// checkMaximalMatching checks that matching is made of edges of g of
+// which no two share a vertex, and that no other edge can be added.
+func checkMaximalMatching(t *testing.T, name string, g *UndirectedGraph, matching Edges) {
+	t.Helper()
+	edges := make(map[Edge]bool)
+	for _, e := range g.Edges() {
+		edges[e] = true
+	}
+	matched := make(map[Vertex]bool)
+	for _, e := range matching {
+		if !edges[e] {
+			t.Errorf("%s: matched edge %v is not in the graph", name, e)
+		}
+		if e.Start == e.End || matched[e.Start] || matched[e.End] {
+			t.Errorf("%s: matched edge %s shares a vertex with another", name, e.ID)
+		}
+		matched[e.Start], matched[e.End] = true, true
+	}
+	for _, e := range g.Edges() {
+		if e.Start != e.End && !matched[e.Start] && !matched[e.End] {
+			t.Errorf("%s: edge %s could be added to the matching", name, e.ID)
+		}
+	}
+}
+
+func TestGreedyMatching(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		name := "random graph " + strconv.Itoa(i)
+		g := randomGraph(r, 2+r.Intn(20), r.Intn(40))
+		seed := r.Int63()
+
+		matching := g.GreedyMatching(MatchingOptions{Iterations: 3, RandomOptions: RandomOptions{Seed: seed}})
+		checkMaximalMatching(t, name, g, matching)
+		// A maximal matching has at least half as many edges as a maximum one
+		if maximum := len(g.BlossomMatching()); len(matching) > maximum || 2*len(matching) < maximum {
+			t.Errorf("%s: matched %d edges, the maximum is %d", name, len(matching), maximum)
+		}
+
+		// The same seed, or a source seeded with it, gives the same result
+		again := g.GreedyMatching(MatchingOptions{Iterations: 3, RandomOptions: RandomOptions{Seed: seed}})
+		source := g.GreedyMatching(MatchingOptions{Iterations: 3, RandomOptions: RandomOptions{Rand: rand.New(rand.NewSource(seed))}})
+		if fmt.Sprint(again) != fmt.Sprint(matching) || fmt.Sprint(source) != fmt.Sprint(matching) {
+			t.Errorf("%s: seed %d gave %v, then %v, and as a source %v", name, seed, matching, again, source)
+		}
+	}
+}
+
+func TestGreedyMatchingSize(t *testing.T) {
+	tests := []struct {
+		name string
+		g    *UndirectedGraph
+		size int
+	}{
+		{"empty", &UndirectedGraph{}, 0},
+		{"disjoint edges", undirectedGraph("a b", "c d", "e f"), 3},
+		// The mono edges a b and c d are preferred over b c
+		{"path", undirectedGraph("a b", "b c", "c d"), 2},
+	}
+
+	for _, test := range tests {
+		matching := test.g.GreedyMatching(MatchingOptions{Iterations: 10})
+		checkMaximalMatching(t, test.name, test.g, matching)
+		if len(matching) != test.size {
+			t.Errorf("%s: matched %d edges %v, want %d", test.name, len(matching), matching, test.size)
+		}
+	}
+}
+
+func TestColors(t *testing.T) {
+	g := undirectedGraph("a b", "b c", "c a", "c d", "d e")
+
+	vertexColors := g.VertexColors()
+	for _, e := range g.Edges() {
+		if vertexColors[e.Start] == vertexColors[e.End] {
+			t.Errorf("both ends of %s have color %d", e.ID, vertexColors[e.Start])
+		}
+	}
+	// Vertices are colored in insertion order
+	if got, want := fmt.Sprint(vertexColors), "map[{a}:0 {b}:1 {c}:2 {d}:0 {e}:1]"; got != want {
+		t.Errorf("VertexColors = %s, want %s", got, want)
+	}
+
+	edgeColors := g.EdgeColors()
+	for _, e := range g.Edges() {
+		for _, f := range g.Edges() {
+			adjacent := e.Start == f.Start || e.Start == f.End || e.End == f.Start || e.End == f.End
+			if e != f && adjacent && edgeColors[e] == edgeColors[f] {
+				t.Errorf("adjacent edges %s and %s have color %d", e.ID, f.ID, edgeColors[e])
+			}
+		}
+	}
+	for i := 0; i < 10; i++ {
+		if again := g.EdgeColors(); fmt.Sprint(again) != fmt.Sprint(edgeColors) {
+			t.Fatalf("EdgeColors = %v, then %v", edgeColors, again)
+		}
+	}
+}
@@ -0,0 +1,687 @@
+package graph
+
+import "errors"
+
+// ErrNoPerfectMatching is returned by MinWeightPerfectMatching when
+// no matching covers every vertex of the graph.
+var ErrNoPerfectMatching = errors.New("graph: graph has no perfect matching")
+
+// MaxWeightMatching finds a matching whose edges have the largest
+// possible total weight, using Edmonds' primal-dual blossom algorithm
+// as described by Galil ("Efficient algorithms for finding maximum
+// matching in graphs", 1986), in O(V^3) time. Edges with a negative
+// weight are never matched, and neither are self loops. The edges are
+// returned in the order they were added to the graph. For bipartite
+// graphs Hungarian gives the same total weight.
+func (g *UndirectedGraphOf[K, W]) MaxWeightMatching() EdgesOf[K, W] {
+	return g.weightedMatching(func(e EdgeOf[K, W]) W { return e.Weight }, false)
+}
+
+// MinWeightPerfectMatching finds a matching which covers every vertex
+// of the graph, with the smallest possible total weight. It returns
+// ErrNoPerfectMatching if there is no such matching, e.g. if the graph
+// has an odd number of vertices.
+func (g *UndirectedGraphOf[K, W]) MinWeightPerfectMatching() (EdgesOf[K, W], error) {
+	if len(g.vertices)%2 != 0 {
+		return nil, ErrNoPerfectMatching
+	}
+
+	// A maximum-cardinality matching of the largest weight in
+	// max - weight + 1 is one of the smallest weight in weight.
+	var max W
+	for i, e := range g.edgeList {
+		if i == 0 || e.Weight > max {
+			max = e.Weight
+		}
+	}
+	matching := g.weightedMatching(func(e EdgeOf[K, W]) W { return max - e.Weight + 1 }, true)
+	if 2*len(matching) != len(g.vertices) {
+		return nil, ErrNoPerfectMatching
+	}
+	return matching, nil
+}
+
+// weightedMatching finds a matching of maximum total weight, with the
+// weights given by weight. If maxCardinality is set, it only considers
+// matchings with as many edges as possible.
+func (g *UndirectedGraphOf[K, W]) weightedMatching(weight func(EdgeOf[K, W]) W, maxCardinality bool) EdgesOf[K, W] {
+	var edges []weightedEdge[W]
+	var matchable []int // [Edge in edges]Index in edgeList
+	for i, e := range g.edgeList {
+		u, v := g.index[e.Start], g.index[e.End]
+		if u != v {
+			edges = append(edges, weightedEdge[W]{u, v, weight(e)})
+			matchable = append(matchable, i)
+		}
+	}
+
+	matched := make([]bool, len(g.edgeList))
+	for _, k := range newWeightedMatching(len(g.vertices), edges, maxCardinality).solve() {
+		matched[matchable[k]] = true
+	}
+
+	var matching EdgesOf[K, W]
+	for i, e := range g.edgeList {
+		if matched[i] {
+			matching = append(matching, e)
+		}
+	}
+	return matching
+}
+
+// weightedEdge is an edge between the vertices i and j.
+type weightedEdge[W Number] struct {
+	i, j   int
+	weight W
+}
+
+// weightedMatching holds the state of the primal-dual blossom algorithm,
+// ported from the Python implementation by Joris van Rantwijk. Vertices
+// are numbered from 0 to n-1 and blossoms from n to 2n-1. The endpoints
+// of edge k are numbered 2k and 2k+1, so that p^1 is the opposite
+// endpoint of p and p/2 its edge.
+//
+// Labels are 1 for S (outer) vertices and blossoms, 2 for T (inner)
+// ones and 0 for those not yet reached from an unmatched vertex.
+type weightedMatching[W Number] struct {
+	n              int
+	edges          []weightedEdge[W]
+	maxCardinality bool
+
+	endpoint  []int   // [Endpoint]Vertex
+	neighbend [][]int // [Vertex]Remote endpoints of its edges
+	mate      []int   // [Vertex]Remote endpoint of its matched edge, -1 if unmatched
+
+	label    []int // [Vertex or blossom]Label
+	labelEnd []int // [Vertex or blossom]Endpoint through which it got its label, -1 if none
+
+	inBlossom      []int   // [Vertex]Top-level blossom it belongs to
+	blossomParent  []int   // [Vertex or blossom]Blossom it is directly part of, -1 if top-level
+	blossomChilds  [][]int // [Blossom]Sub-blossoms, in order around the blossom starting at the base
+	blossomBase    []int   // [Vertex or blossom]Base vertex, -1 for unused blossoms
+	blossomEndps   [][]int // [Blossom]Endpoints of the edges connecting the sub-blossoms
+	unusedBlossoms []int
+
+	// bestEdge is the least slack edge to a different S-blossom, or for
+	// an unlabeled vertex to any S-vertex. blossomBestEdges holds, for
+	// top-level S-blossoms, the least slack edges to other S-blossoms.
+	// A nil list is not known, unlike an empty one.
+	bestEdge         []int
+	blossomBestEdges [][]int
+
+	dualVar   []W    // [Vertex or blossom]Dual variable, doubled for vertices
+	allowEdge []bool // [Edge]Whether the edge has zero slack
+	queue     []int  // S-vertices whose edges are still to be scanned
+}
+
+func newWeightedMatching[W Number](n int, edges []weightedEdge[W], maxCardinality bool) *weightedMatching[W] {
+	m := &weightedMatching[W]{
+		n:                n,
+		edges:            edges,
+		maxCardinality:   maxCardinality,
+		endpoint:         make([]int, 2*len(edges)),
+		neighbend:        make([][]int, n),
+		mate:             make([]int, n),
+		label:            make([]int, 2*n),
+		labelEnd:         make([]int, 2*n),
+		inBlossom:        make([]int, n),
+		blossomParent:    make([]int, 2*n),
+		blossomChilds:    make([][]int, 2*n),
+		blossomBase:      make([]int, 2*n),
+		blossomEndps:     make([][]int, 2*n),
+		bestEdge:         make([]int, 2*n),
+		blossomBestEdges: make([][]int, 2*n),
+		dualVar:          make([]W, 2*n),
+		allowEdge:        make([]bool, len(edges)),
+	}
+
+	var maxWeight W
+	for k, e := range edges {
+		m.endpoint[2*k], m.endpoint[2*k+1] = e.i, e.j
+		m.neighbend[e.i] = append(m.neighbend[e.i], 2*k+1)
+		m.neighbend[e.j] = append(m.neighbend[e.j], 2*k)
+		if e.weight > maxWeight {
+			maxWeight = e.weight
+		}
+	}
+
+	for v := 0; v < n; v++ {
+		m.mate[v] = -1
+		m.inBlossom[v] = v
+		m.blossomBase[v] = v
+		m.blossomBase[n+v] = -1
+		m.dualVar[v] = maxWeight
+		m.unusedBlossoms = append(m.unusedBlossoms, n+v)
+	}
+	for b := range m.labelEnd {
+		m.labelEnd[b] = -1
+		m.blossomParent[b] = -1
+		m.bestEdge[b] = -1
+	}
+
+	return m
+}
+
+// slack returns the slack of edge k, which is never negative.
+func (m *weightedMatching[W]) slack(k int) W {
+	e := m.edges[k]
+	return m.dualVar[e.i] + m.dualVar[e.j] - 2*e.weight
+}
+
+// blossomLeaves returns the vertices in blossom b.
+func (m *weightedMatching[W]) blossomLeaves(b int) []int {
+	return m.appendLeaves(nil, b)
+}
+
+func (m *weightedMatching[W]) appendLeaves(leaves []int, b int) []int {
+	if b < m.n {
+		return append(leaves, b)
+	}
+	for _, t := range m.blossomChilds[b] {
+		leaves = m.appendLeaves(leaves, t)
+	}
+	return leaves
+}
+
+// assignLabel labels w and its top-level blossom with t, reached
+// through endpoint p. T-blossoms pass an S label on to their mate.
+func (m *weightedMatching[W]) assignLabel(w, t, p int) {
+	b := m.inBlossom[w]
+	m.label[w], m.label[b] = t, t
+	m.labelEnd[w], m.labelEnd[b] = p, p
+	m.bestEdge[w], m.bestEdge[b] = -1, -1
+	if t == 1 {
+		m.queue = append(m.queue, m.blossomLeaves(b)...)
+	} else if t == 2 {
+		base := m.blossomBase[b]
+		m.assignLabel(m.endpoint[m.mate[base]], 1, m.mate[base]^1)
+	}
+}
+
+// scanBlossom traces back from the S-vertices v and w to find either
+// the base of a new blossom, or an augmenting path, returning -1.
+func (m *weightedMatching[W]) scanBlossom(v, w int) int {
+	var path []int
+	base := -1
+	for v != -1 || w != -1 {
+		b := m.inBlossom[v]
+		if m.label[b]&4 != 0 {
+			base = m.blossomBase[b]
+			break
+		}
+		path = append(path, b)
+		m.label[b] = 5 // Mark as visited
+		if m.labelEnd[b] == -1 {
+			v = -1 // The root of an alternating tree
+		} else {
+			v = m.endpoint[m.labelEnd[b]]
+			b = m.inBlossom[v]
+			v = m.endpoint[m.labelEnd[b]]
+		}
+		if w != -1 {
+			v, w = w, v
+		}
+	}
+	for _, b := range path {
+		m.label[b] = 1
+	}
+	return base
+}
+
+// addBlossom makes a new blossom with the given base, out of the
+// cycle closed by edge k between two S-vertices.
+func (m *weightedMatching[W]) addBlossom(base, k int) {
+	v, w := m.edges[k].i, m.edges[k].j
+	bb, bv, bw := m.inBlossom[base], m.inBlossom[v], m.inBlossom[w]
+
+	b := m.unusedBlossoms[len(m.unusedBlossoms)-1]
+	m.unusedBlossoms = m.unusedBlossoms[:len(m.unusedBlossoms)-1]
+	m.blossomBase[b] = base
+	m.blossomParent[b] = -1
+	m.blossomParent[bb] = b
+
+	// Trace back from v to the base, then from w
+	var path, endps []int
+	for bv != bb {
+		m.blossomParent[bv] = b
+		path = append(path, bv)
+		endps = append(endps, m.labelEnd[bv])
+		v = m.endpoint[m.labelEnd[bv]]
+		bv = m.inBlossom[v]
+	}
+	path = append(path, bb)
+	reverse(path)
+	reverse(endps)
+	endps = append(endps, 2*k)
+	for bw != bb {
+		m.blossomParent[bw] = b
+		path = append(path, bw)
+		endps = append(endps, m.labelEnd[bw]^1)
+		w = m.endpoint[m.labelEnd[bw]]
+		bw = m.inBlossom[w]
+	}
+	m.blossomChilds[b] = path
+	m.blossomEndps[b] = endps
+
+	m.label[b] = 1
+	m.labelEnd[b] = m.labelEnd[bb]
+	m.dualVar[b] = 0
+	for _, v := range m.blossomLeaves(b) {
+		if m.label[m.inBlossom[v]] == 2 {
+			// Former T-vertices are now S-vertices to be scanned
+			m.queue = append(m.queue, v)
+		}
+		m.inBlossom[v] = b
+	}
+
+	// Compute the least slack edges to other S-blossoms
+	bestEdgeTo := make([]int, 2*m.n)
+	for i := range bestEdgeTo {
+		bestEdgeTo[i] = -1
+	}
+	for _, bv := range path {
+		var lists [][]int
+		if m.blossomBestEdges[bv] == nil {
+			for _, v := range m.blossomLeaves(bv) {
+				list := make([]int, 0, len(m.neighbend[v]))
+				for _, p := range m.neighbend[v] {
+					list = append(list, p/2)
+				}
+				lists = append(lists, list)
+			}
+		} else {
+			lists = [][]int{m.blossomBestEdges[bv]}
+		}
+		for _, list := range lists {
+			for _, k := range list {
+				i, j := m.edges[k].i, m.edges[k].j
+				if m.inBlossom[j] == b {
+					i, j = j, i
+				}
+				bj := m.inBlossom[j]
+				if bj != b && m.label[bj] == 1 && (bestEdgeTo[bj] == -1 || m.slack(k) < m.slack(bestEdgeTo[bj])) {
+					bestEdgeTo[bj] = k
+				}
+			}
+		}
+		m.blossomBestEdges[bv] = nil
+		m.bestEdge[bv] = -1
+	}
+
+	best := []int{} // Not nil, the best edges are known
+	m.bestEdge[b] = -1
+	for _, k := range bestEdgeTo {
+		if k == -1 {
+			continue
+		}
+		best = append(best, k)
+		if m.bestEdge[b] == -1 || m.slack(k) < m.slack(m.bestEdge[b]) {
+			m.bestEdge[b] = k
+		}
+	}
+	m.blossomBestEdges[b] = best
+}
+
+// expandBlossom turns the sub-blossoms of b into top-level blossoms.
+// At the end of a stage this is done recursively for every blossom
+// whose dual variable is zero, otherwise the labels of the T-blossom
+// b are passed on to the sub-blossoms on the path through it.
+func (m *weightedMatching[W]) expandBlossom(b int, endStage bool) {
+	for _, s := range m.blossomChilds[b] {
+		m.blossomParent[s] = -1
+		if s < m.n {
+			m.inBlossom[s] = s
+		} else if endStage && m.dualVar[s] == 0 {
+			m.expandBlossom(s, endStage)
+		} else {
+			for _, v := range m.blossomLeaves(s) {
+				m.inBlossom[v] = s
+			}
+		}
+	}
+
+	if !endStage && m.label[b] == 2 {
+		// Relabel the sub-blossoms along the even path from the one
+		// the blossom was entered through to the base
+		childs, endps := m.blossomChilds[b], m.blossomEndps[b]
+		entryChild := m.inBlossom[m.endpoint[m.labelEnd[b]^1]]
+		j := indexOf(childs, entryChild)
+		jStep, endpTrick := -1, 1
+		if j&1 != 0 {
+			// Go forward and wrap around
+			j -= len(childs)
+			jStep, endpTrick = 1, 0
+		}
+		at := func(list []int, i int) int {
+			if i < 0 {
+				i += len(list)
+			}
+			return list[i]
+		}
+
+		p := m.labelEnd[b]
+		for j != 0 {
+			m.label[m.endpoint[p^1]] = 0
+			m.label[m.endpoint[at(endps, j-endpTrick)^endpTrick^1]] = 0
+			m.assignLabel(m.endpoint[p^1], 2, p)
+			m.allowEdge[at(endps, j-endpTrick)/2] = true
+			j += jStep
+			p = at(endps, j-endpTrick) ^ endpTrick
+			m.allowEdge[p/2] = true
+			j += jStep
+		}
+		// The base becomes a T-blossom, without passing its label on
+		bv := at(childs, j)
+		m.label[m.endpoint[p^1]], m.label[bv] = 2, 2
+		m.labelEnd[m.endpoint[p^1]], m.labelEnd[bv] = p, p
+		m.bestEdge[bv] = -1
+		j += jStep
+
+		// The sub-blossoms on the odd path lose their labels, unless
+		// they can be reached some other way
+		for at(childs, j) != entryChild {
+			bv := at(childs, j)
+			if m.label[bv] == 1 {
+				j += jStep
+				continue
+			}
+			leaves := m.blossomLeaves(bv)
+			v := leaves[len(leaves)-1]
+			for _, leaf := range leaves {
+				if m.label[leaf] != 0 {
+					v = leaf
+					break
+				}
+			}
+			if m.label[v] != 0 {
+				m.label[v] = 0
+				m.label[m.endpoint[m.mate[m.blossomBase[bv]]]] = 0
+				m.assignLabel(v, 2, m.labelEnd[v])
+			}
+			j += jStep
+		}
+	}
+
+	m.label[b], m.labelEnd[b] = -1, -1
+	m.blossomChilds[b], m.blossomEndps[b] = nil, nil
+	m.blossomBase[b] = -1
+	m.blossomBestEdges[b] = nil
+	m.bestEdge[b] = -1
+	m.unusedBlossoms = append(m.unusedBlossoms, b)
+}
+
+// augmentBlossom swaps the matched and unmatched edges on the path
+// through blossom b from vertex v to its base, making v the new base.
+func (m *weightedMatching[W]) augmentBlossom(b, v int) {
+	t := v
+	for m.blossomParent[t] != b {
+		t = m.blossomParent[t]
+	}
+	if t >= m.n {
+		m.augmentBlossom(t, v)
+	}
+
+	childs, endps := m.blossomChilds[b], m.blossomEndps[b]
+	i := indexOf(childs, t)
+	j := i
+	jStep, endpTrick := -1, 1
+	if i&1 != 0 {
+		j -= len(childs)
+		jStep, endpTrick = 1, 0
+	}
+	at := func(list []int, i int) int {
+		if i < 0 {
+			i += len(list)
+		}
+		return list[i]
+	}
+
+	for j != 0 {
+		j += jStep
+		t = at(childs, j)
+		p := at(endps, j-endpTrick) ^ endpTrick
+		if t >= m.n {
+			m.augmentBlossom(t, m.endpoint[p])
+		}
+		j += jStep
+		t = at(childs, j)
+		if t >= m.n {
+			m.augmentBlossom(t, m.endpoint[p^1])
+		}
+		m.mate[m.endpoint[p]] = p ^ 1
+		m.mate[m.endpoint[p^1]] = p
+	}
+
+	// Rotate the sub-blossoms so the new base comes first
+	m.blossomChilds[b] = append(append([]int{}, childs[i:]...), childs[:i]...)
+	m.blossomEndps[b] = append(append([]int{}, endps[i:]...), endps[:i]...)
+	m.blossomBase[b] = m.blossomBase[m.blossomChilds[b][0]]
+}
+
+// augmentMatching swaps the matched and unmatched edges along the
+// augmenting path through edge k, between two S-vertices.
+func (m *weightedMatching[W]) augmentMatching(k int) {
+	v, w := m.edges[k].i, m.edges[k].j
+	for _, start := range [][2]int{{v, 2*k + 1}, {w, 2 * k}} {
+		s, p := start[0], start[1]
+		for {
+			bs := m.inBlossom[s]
+			if bs >= m.n {
+				m.augmentBlossom(bs, s)
+			}
+			m.mate[s] = p
+			if m.labelEnd[bs] == -1 {
+				break // Reached the root
+			}
+			t := m.endpoint[m.labelEnd[bs]]
+			bt := m.inBlossom[t]
+			s = m.endpoint[m.labelEnd[bt]]
+			j := m.endpoint[m.labelEnd[bt]^1]
+			if bt >= m.n {
+				m.augmentBlossom(bt, j)
+			}
+			m.mate[j] = m.labelEnd[bt]
+			p = m.labelEnd[bt] ^ 1
+		}
+	}
+}
+
+// solve runs the algorithm and returns the matched edges.
+func (m *weightedMatching[W]) solve() []int {
+	n := m.n
+	// Every stage either augments the matching or ends the search
+	for stage := 0; stage < n; stage++ {
+		for b := range m.label {
+			m.label[b] = 0
+			m.bestEdge[b] = -1
+		}
+		for b := n; b < 2*n; b++ {
+			m.blossomBestEdges[b] = nil
+		}
+		for k := range m.allowEdge {
+			m.allowEdge[k] = false
+		}
+		m.queue = m.queue[:0]
+
+		for v := 0; v < n; v++ {
+			if m.mate[v] == -1 && m.label[m.inBlossom[v]] == 0 {
+				m.assignLabel(v, 1, -1)
+			}
+		}
+
+		augmented := false
+		for {
+			// Grow the alternating trees along edges of zero slack
+			for len(m.queue) > 0 && !augmented {
+				v := m.queue[len(m.queue)-1]
+				m.queue = m.queue[:len(m.queue)-1]
+
+				for _, p := range m.neighbend[v] {
+					k := p / 2
+					w := m.endpoint[p]
+					if m.inBlossom[v] == m.inBlossom[w] {
+						continue
+					}
+					var kSlack W
+					if !m.allowEdge[k] {
+						if kSlack = m.slack(k); kSlack <= 0 {
+							m.allowEdge[k] = true
+						}
+					}
+
+					if m.allowEdge[k] {
+						if m.label[m.inBlossom[w]] == 0 {
+							m.assignLabel(w, 2, p^1)
+						} else if m.label[m.inBlossom[w]] == 1 {
+							if base := m.scanBlossom(v, w); base >= 0 {
+								m.addBlossom(base, k)
+							} else {
+								m.augmentMatching(k)
+								augmented = true
+								break
+							}
+						} else if m.label[w] == 0 {
+							// w is inside a T-blossom, but not yet reached
+							m.label[w] = 2
+							m.labelEnd[w] = p ^ 1
+						}
+					} else if m.label[m.inBlossom[w]] == 1 {
+						if b := m.inBlossom[v]; m.bestEdge[b] == -1 || kSlack < m.slack(m.bestEdge[b]) {
+							m.bestEdge[b] = k
+						}
+					} else if m.label[w] == 0 {
+						if m.bestEdge[w] == -1 || kSlack < m.slack(m.bestEdge[w]) {
+							m.bestEdge[w] = k
+						}
+					}
+				}
+			}
+			if augmented {
+				break
+			}
+
+			// No more progress, update the dual variables by the
+			// largest delta which keeps every slack non-negative
+			deltaType, deltaEdge, deltaBlossom := -1, -1, -1
+			var delta W
+			if !m.maxCardinality {
+				// The smallest vertex dual, which ends the search at zero
+				deltaType = 1
+				delta = minOf(m.dualVar[:n])
+			}
+			for v := 0; v < n; v++ {
+				// Edges from S-vertices to free vertices
+				if m.label[m.inBlossom[v]] == 0 && m.bestEdge[v] != -1 {
+					if d := m.slack(m.bestEdge[v]); deltaType == -1 || d < delta {
+						delta, deltaType, deltaEdge = d, 2, m.bestEdge[v]
+					}
+				}
+			}
+			for b := 0; b < 2*n; b++ {
+				// Edges between S-blossoms, whose slack is even for integers
+				if m.blossomParent[b] == -1 && m.label[b] == 1 && m.bestEdge[b] != -1 {
+					if d := m.slack(m.bestEdge[b]) / 2; deltaType == -1 || d < delta {
+						delta, deltaType, deltaEdge = d, 3, m.bestEdge[b]
+					}
+				}
+			}
+			for b := n; b < 2*n; b++ {
+				// T-blossoms, which can be expanded at zero
+				if m.blossomBase[b] >= 0 && m.blossomParent[b] == -1 && m.label[b] == 2 && (deltaType == -1 || m.dualVar[b] < delta) {
+					delta, deltaType, deltaBlossom = m.dualVar[b], 4, b
+				}
+			}
+			if deltaType == -1 {
+				// Only with maxCardinality, when nothing more can be done
+				deltaType = 1
+				delta = minOf(m.dualVar[:n])
+				if delta < 0 {
+					delta = 0
+				}
+			}
+
+			for v := 0; v < n; v++ {
+				switch m.label[m.inBlossom[v]] {
+				case 1:
+					m.dualVar[v] -= delta
+				case 2:
+					m.dualVar[v] += delta
+				}
+			}
+			for b := n; b < 2*n; b++ {
+				if m.blossomBase[b] >= 0 && m.blossomParent[b] == -1 {
+					switch m.label[b] {
+					case 1:
+						m.dualVar[b] += delta
+					case 2:
+						m.dualVar[b] -= delta
+					}
+				}
+			}
+
+			if deltaType == 1 {
+				break // The matching is optimal
+			}
+			switch deltaType {
+			case 2:
+				m.allowEdge[deltaEdge] = true
+				i, j := m.edges[deltaEdge].i, m.edges[deltaEdge].j
+				if m.label[m.inBlossom[i]] == 0 {
+					i = j
+				}
+				m.queue = append(m.queue, i)
+			case 3:
+				m.allowEdge[deltaEdge] = true
+				m.queue = append(m.queue, m.edges[deltaEdge].i)
+			case 4:
+				m.expandBlossom(deltaBlossom, false)
+			}
+		}
+
+		if !augmented {
+			break
+		}
+		// Expand the S-blossoms which are no longer needed
+		for b := n; b < 2*n; b++ {
+			if m.blossomParent[b] == -1 && m.blossomBase[b] >= 0 && m.label[b] == 1 && m.dualVar[b] == 0 {
+				m.expandBlossom(b, true)
+			}
+		}
+	}
+
+	var matched []int
+	for v, p := range m.mate {
+		if p >= 0 && v < m.endpoint[p] {
+			matched = append(matched, p/2)
+		}
+	}
+	return matched
+}
+
+// indexOf returns the position of x in list, or -1.
+func indexOf(list []int, x int) int {
+	for i, y := range list {
+		if y == x {
+			return i
+		}
+	}
+	return -1
+}
+
+// reverse reverses list in place.
+func reverse(list []int) {
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+}
+
+// minOf returns the smallest of values, which must not be empty.
+func minOf[W Number](values []W) W {
+	min := values[0]
+	for _, value := range values[1:] {
+		if value < min {
+			min = value
+		}
+	}
+	return min
+}
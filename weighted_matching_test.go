@@ -0,0 +1,155 @@
+package graph
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+// matchingWeight returns the total weight of the edges.
+func matchingWeight(edges []Edge) int64 {
+	var weight int64
+	for _, e := range edges {
+		weight += e.Weight
+	}
+	return weight
+}
+
+// bruteForceMaxWeight returns the largest total weight of a matching of g.
+func bruteForceMaxWeight(g *UndirectedGraph) int64 {
+	var max int64
+	forEachMatching(g, func(matching []Edge) {
+		if weight := matchingWeight(matching); weight > max {
+			max = weight
+		}
+	})
+	return max
+}
+
+// bruteForceMinWeightPerfect returns the smallest total weight of a
+// perfect matching of g, and whether there is one.
+func bruteForceMinWeightPerfect(g *UndirectedGraph) (int64, bool) {
+	var min int64
+	found := false
+	forEachMatching(g, func(matching []Edge) {
+		if 2*len(matching) != g.VertexCount() {
+			return
+		}
+		if weight := matchingWeight(matching); !found || weight < min {
+			min, found = weight, true
+		}
+	})
+	return min, found
+}
+
+func TestMaxWeightMatching(t *testing.T) {
+	tests := []struct {
+		name   string
+		g      *UndirectedGraph
+		weight int64
+	}{
+		{"empty", &UndirectedGraph{}, 0},
+		{"single edge", undirectedGraph("a b 3"), 3},
+		{"negative edge", undirectedGraph("a b -3"), 0},
+		{"self loop", undirectedGraph("a a 5"), 0},
+		// One heavy edge beats two light ones
+		{"path", undirectedGraph("a b 2", "b c 5", "c d 2"), 5},
+		{"two beat one", undirectedGraph("a b 3", "b c 5", "c d 3"), 6},
+		{"parallel edges", undirectedGraph("a b 1", "a b 4", "b a 2"), 4},
+		{"triangle", undirectedGraph("a b 2", "b c 3", "c a 4"), 4},
+		// The heaviest edge c a of the blossom a b c is left out
+		{"blossom", undirectedGraph("a b 8", "b c 9", "c a 10", "c d 7", "a e 1"), 15},
+	}
+
+	for _, test := range tests {
+		matching := test.g.MaxWeightMatching()
+		checkMatching(t, test.name, test.g, matching)
+		if weight := matchingWeight(matching); weight != test.weight {
+			t.Errorf("%s: matched %v of weight %d, want %d", test.name, matching, weight, test.weight)
+		}
+	}
+}
+
+func TestMaxWeightMatchingBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		name := "random graph " + strconv.Itoa(i)
+		g := randomGraph(r, 2+r.Intn(9), r.Intn(16))
+
+		matching := g.MaxWeightMatching()
+		checkMatching(t, name, g, matching)
+		if weight, want := matchingWeight(matching), bruteForceMaxWeight(g); weight != want {
+			t.Errorf("%s: matched weight %d, want %d\n%v", name, weight, want, g.Edges())
+		}
+	}
+}
+
+func TestMinWeightPerfectMatching(t *testing.T) {
+	tests := []struct {
+		name   string
+		g      *UndirectedGraph
+		weight int64
+		err    error
+	}{
+		{"empty", &UndirectedGraph{}, 0, nil},
+		{"single edge", undirectedGraph("a b 3"), 3, nil},
+		{"negative weights", undirectedGraph("a b -3", "c d -2", "a c 1", "b d 1"), -5, nil},
+		{"cheaper pair", undirectedGraph("a b 1", "b c 1", "c d 1", "d a 5", "a c 0", "b d 0"), 0, nil},
+		// A maximum-weight matching would take b c alone
+		{"perfect over heavy", undirectedGraph("a b 1", "b c 10", "c d 1"), 2, nil},
+		{"odd vertex count", undirectedGraph("a b 1", "b c 1"), 0, ErrNoPerfectMatching},
+		{"star", undirectedGraph("a b 1", "a c 1", "a d 1", "e f 1"), 0, ErrNoPerfectMatching},
+		{"isolated vertices", undirectedGraph("a b 1", "c c 1", "d d 1"), 0, ErrNoPerfectMatching},
+	}
+
+	for _, test := range tests {
+		matching, err := test.g.MinWeightPerfectMatching()
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: MinWeightPerfectMatching returned %v, want %v", test.name, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		checkMatching(t, test.name, test.g, matching)
+		if 2*len(matching) != test.g.VertexCount() {
+			t.Errorf("%s: matching %v is not perfect", test.name, matching)
+		}
+		if weight := matchingWeight(matching); weight != test.weight {
+			t.Errorf("%s: matched %v of weight %d, want %d", test.name, matching, weight, test.weight)
+		}
+	}
+}
+
+func TestMinWeightPerfectMatchingBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		name := "random graph " + strconv.Itoa(i)
+		g := randomGraph(r, 2*(1+r.Intn(4)), r.Intn(16))
+
+		want, perfect := bruteForceMinWeightPerfect(g)
+		matching, err := g.MinWeightPerfectMatching()
+		if !perfect {
+			if !errors.Is(err, ErrNoPerfectMatching) {
+				t.Errorf("%s: MinWeightPerfectMatching returned %v, want %v\n%v", name, err, ErrNoPerfectMatching, g.Edges())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: MinWeightPerfectMatching failed: %s\n%v", name, err, g.Edges())
+			continue
+		}
+		checkMatching(t, name, g, matching)
+		if weight := matchingWeight(matching); 2*len(matching) != g.VertexCount() || weight != want {
+			t.Errorf("%s: matched %d edges of weight %d, want a perfect matching of weight %d\n%v", name, len(matching), weight, want, g.Edges())
+		}
+	}
+}
+
+func BenchmarkMaxWeightMatching(b *testing.B) {
+	g := randomGraph(rand.New(rand.NewSource(1)), 200, 1000)
+	for i := 0; i < b.N; i++ {
+		g.MaxWeightMatching()
+	}
+}